@@ -0,0 +1,169 @@
+package xlsxformula
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Maximum row and column numbers of a worksheet.
+const (
+	MaxRows    = 1048576
+	MaxColumns = 16384
+)
+
+// cellAddress is a parsed A1 style cell reference. Row and Col are 1 origin.
+// Row or Col is 0 for the omitted part of a range like "B" of "A1:B".
+type cellAddress struct {
+	Row    int
+	Col    int
+	RowAbs bool
+	ColAbs bool
+}
+
+func (c cellAddress) String() string {
+	var builder strings.Builder
+	if c.ColAbs {
+		builder.WriteByte('$')
+	}
+	builder.WriteString(columnName(c.Col))
+	if c.Row == 0 {
+		return builder.String()
+	}
+	if c.RowAbs {
+		builder.WriteByte('$')
+	}
+	builder.WriteString(strconv.Itoa(c.Row))
+	return builder.String()
+}
+
+// parseCellAddress parses a reference like "B3" or "$A$1".
+func parseCellAddress(text string) (cellAddress, bool) {
+	var address cellAddress
+	i := 0
+	if i < len(text) && text[i] == '$' {
+		address.ColAbs = true
+		i++
+	}
+	start := i
+	for i < len(text) && isASCIILetter(text[i]) {
+		i++
+	}
+	if start == i {
+		return address, false
+	}
+	col, ok := columnNumber(text[start:i])
+	if !ok {
+		return address, false
+	}
+	if i < len(text) && text[i] == '$' {
+		address.RowAbs = true
+		i++
+	}
+	row, err := strconv.Atoi(text[i:])
+	if err != nil || row < 1 || row > MaxRows || text[i] == '+' || text[i] == '-' {
+		return address, false
+	}
+	address.Row = row
+	address.Col = col
+	return address, true
+}
+
+// parseReferencePart parses one side of a range. Unlike parseCellAddress, it accepts
+// only a column ("$B") or only a row ("3").
+func parseReferencePart(text string) (cellAddress, bool) {
+	if address, ok := parseCellAddress(text); ok {
+		return address, true
+	}
+	var address cellAddress
+	i := 0
+	if i < len(text) && text[i] == '$' {
+		i++
+	}
+	if i < len(text) && isASCIILetter(text[i]) {
+		col, ok := columnNumber(text[i:])
+		address.Col = col
+		address.ColAbs = i == 1
+		return address, ok
+	}
+	row, err := strconv.Atoi(text[i:])
+	if err != nil || row < 1 || row > MaxRows || text[i] == '+' || text[i] == '-' {
+		return address, false
+	}
+	address.Row = row
+	address.RowAbs = i == 1
+	return address, true
+}
+
+// parseArea parses "A1", "A1:B3", "A:B" or "1:3" into top-left and bottom-right cells.
+// Whole columns have rows from 1 to MaxRows and whole rows have columns from 1 to MaxColumns.
+func parseArea(text string) (cellAddress, cellAddress, bool) {
+	parts := strings.Split(text, ":")
+	if len(parts) > 2 {
+		return cellAddress{}, cellAddress{}, false
+	}
+	start, ok := parseReferencePart(parts[0])
+	if !ok {
+		return start, start, false
+	}
+	end := start
+	if len(parts) == 2 {
+		if end, ok = parseReferencePart(parts[1]); !ok {
+			return start, end, false
+		}
+	} else if start.Row == 0 || start.Col == 0 {
+		// "A" or "1" alone is not a reference
+		return start, end, false
+	}
+	if start.Row == 0 || end.Row == 0 {
+		start.Row, end.Row = 1, MaxRows
+	}
+	if start.Col == 0 || end.Col == 0 {
+		start.Col, end.Col = 1, MaxColumns
+	}
+	if start.Row > end.Row {
+		start.Row, end.Row = end.Row, start.Row
+		start.RowAbs, end.RowAbs = end.RowAbs, start.RowAbs
+	}
+	if start.Col > end.Col {
+		start.Col, end.Col = end.Col, start.Col
+		start.ColAbs, end.ColAbs = end.ColAbs, start.ColAbs
+	}
+	return start, end, true
+}
+
+// columnNumber converts column name like "AB" to 1 origin number.
+func columnNumber(name string) (int, bool) {
+	if name == "" || len(name) > 3 {
+		return 0, false
+	}
+	col := 0
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+		if ch < 'A' || ch > 'Z' {
+			return 0, false
+		}
+		col = col*26 + int(ch-'A'+1)
+	}
+	if col > MaxColumns {
+		return 0, false
+	}
+	return col, true
+}
+
+// columnName converts 1 origin column number to name like "AB".
+func columnName(col int) string {
+	var name []byte
+	for col > 0 {
+		col--
+		name = append([]byte{byte('A' + col%26)}, name...)
+		col /= 26
+	}
+	return string(name)
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z')
+}
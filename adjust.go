@@ -0,0 +1,143 @@
+package xlsxformula
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EditType is a kind of structural edit of a sheet.
+type EditType int
+
+const (
+	InsertRows EditType = iota
+	DeleteRows
+	InsertColumns
+	DeleteColumns
+)
+
+// SheetEdit is a structural edit like "insert 2 rows at row 5 on Sheet1".
+type SheetEdit struct {
+	Type  EditType
+	Sheet string // edited sheet name
+	Index int    // first row or column number (1 origin) to be inserted or deleted
+	Count int    // number of rows or columns
+}
+
+// AdjustReferences returns a copy of node whose Range tokens follow the edit like Excel does.
+// sheet is the name of the sheet that has the formula; references without sheet name refer to it.
+// Ranges straddling the inserted position expand, ranges lose the deleted rows (columns) and
+// references that are deleted entirely become #REF!. Absolute references ($A$1) are also adjusted.
+// Names and 3D references are not changed.
+func AdjustReferences(node *Node, sheet string, edit SheetEdit) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("node is nil")
+	}
+	if edit.Index < 1 || edit.Count < 1 {
+		return nil, fmt.Errorf("Invalid edit: index %d, count %d", edit.Index, edit.Count)
+	}
+	return transformTokens(node, func(token *Token) (*Token, error) {
+		if token.Type != Range || token.LastSheet != "" || token.Workbook != "" {
+			return token, nil
+		}
+		target := token.Sheet
+		if target == "" {
+			target = sheet
+		}
+		if !strings.EqualFold(target, edit.Sheet) {
+			return token, nil
+		}
+		parts := strings.Split(token.Text, ":")
+		addresses := make([]cellAddress, len(parts))
+		for i, part := range parts {
+			address, ok := parseReferencePart(part)
+			if !ok {
+				return nil, fmt.Errorf("Invalid range '%s' at %d:%d", token.Text, token.Line, token.Col)
+			}
+			addresses[i] = address
+		}
+		first, last := &addresses[0], &addresses[len(addresses)-1]
+		var ok bool
+		switch edit.Type {
+		case InsertRows, DeleteRows:
+			if first.Row > last.Row && last.Row != 0 {
+				first.Row, last.Row = last.Row, first.Row
+			}
+			ok = edit.adjustSpan(&first.Row, &last.Row, MaxRows)
+		case InsertColumns, DeleteColumns:
+			if first.Col > last.Col && last.Col != 0 {
+				first.Col, last.Col = last.Col, first.Col
+			}
+			ok = edit.adjustSpan(&first.Col, &last.Col, MaxColumns)
+		}
+		if !ok {
+			return refErrorToken(token), nil
+		}
+		for i, address := range addresses {
+			parts[i] = address.String()
+		}
+		if len(parts) == 2 && parts[0] == parts[1] && first.Row != 0 && first.Col != 0 {
+			// B1:D1 becomes B1 after deleting C:D
+			parts = parts[:1]
+		}
+		result := *token
+		result.Text = strings.Join(parts, ":")
+		return &result, nil
+	})
+}
+
+// adjustSpan moves rows (or columns) first..last. 0 means the omitted part like the row of "A:A" and
+// it is not changed. It returns false if all of them are deleted or pushed out of the sheet.
+func (edit SheetEdit) adjustSpan(first, last *int, max int) bool {
+	if *first == 0 || *last == 0 {
+		// single part ("A1") or mixed range like "A1:B"
+		if *first != 0 {
+			return edit.adjustSingle(first, max)
+		} else if *last != 0 {
+			return edit.adjustSingle(last, max)
+		}
+		return true
+	}
+	single := first == last
+	end := edit.Index + edit.Count - 1
+	switch edit.Type {
+	case InsertRows, InsertColumns:
+		if *first >= edit.Index {
+			*first += edit.Count
+		}
+		if !single && *last >= edit.Index {
+			*last += edit.Count
+		}
+		if *first > max {
+			return false
+		}
+		if *last > max {
+			*last = max
+		}
+	default:
+		if *first >= edit.Index && *last <= end {
+			return false
+		}
+		if single {
+			if *first > end {
+				*first -= edit.Count
+			}
+			return true
+		}
+		if *first > end {
+			*first -= edit.Count
+		} else if *first >= edit.Index {
+			*first = edit.Index
+		}
+		if *last > end {
+			*last -= edit.Count
+		} else if *last >= edit.Index {
+			*last = edit.Index - 1
+		}
+	}
+	return true
+}
+
+func (edit SheetEdit) adjustSingle(position *int, max int) bool {
+	return edit.adjustSpan(position, position, max)
+}
@@ -0,0 +1,50 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+func TestAdjustReferences(t *testing.T) {
+	tests := []struct {
+		formula  string
+		edit     SheetEdit
+		expected string
+	}{
+		{"A5+$A$5+A2", SheetEdit{Type: InsertRows, Sheet: "Sheet1", Index: 3, Count: 2}, "A7+$A$7+A2"},
+		{"SUM(A1:A10)", SheetEdit{Type: InsertRows, Sheet: "Sheet1", Index: 5, Count: 2}, "SUM(A1:A12)"},
+		{"SUM(A5:A10)", SheetEdit{Type: InsertRows, Sheet: "Sheet1", Index: 5, Count: 2}, "SUM(A7:A12)"},
+		{"SUM(A1:A1048576)", SheetEdit{Type: InsertRows, Sheet: "Sheet1", Index: 5, Count: 2}, "SUM(A1:A1048576)"},
+		{"A1048576", SheetEdit{Type: InsertRows, Sheet: "Sheet1", Index: 5, Count: 1}, "#REF!"},
+		{"SUM(A1:A10)", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 3, Count: 2}, "SUM(A1:A8)"},
+		{"SUM(A3:A10)", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 1, Count: 4}, "SUM(A1:A6)"},
+		{"SUM(A5:A10)", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 8, Count: 5}, "SUM(A5:A7)"},
+		{"SUM(A3:B4)+A3", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 3, Count: 2}, "SUM(#REF!)+#REF!"},
+		{"A10+A1", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 3, Count: 2}, "A8+A1"},
+		{"SUM(B1:D1)+E1", SheetEdit{Type: InsertColumns, Sheet: "Sheet1", Index: 3, Count: 1}, "SUM(B1:E1)+F1"},
+		{"SUM(B1:D1)+$E$1", SheetEdit{Type: DeleteColumns, Sheet: "Sheet1", Index: 3, Count: 2}, "SUM(B1)+$C$1"},
+		{"SUM($B$1:D3)", SheetEdit{Type: DeleteColumns, Sheet: "Sheet1", Index: 1, Count: 2}, "SUM($A$1:B3)"},
+		{"Sheet2!A5+A5", SheetEdit{Type: InsertRows, Sheet: "Sheet2", Index: 1, Count: 1}, "Sheet2!A6+A5"},
+		{"'My Sheet'!A5+Sheet1:Sheet3!A5", SheetEdit{Type: DeleteRows, Sheet: "my sheet", Index: 5, Count: 1}, "'My Sheet'!#REF!+Sheet1:Sheet3!A5"},
+		{"A5+Rate", SheetEdit{Type: InsertRows, Sheet: "Other", Index: 1, Count: 1}, "A5+Rate"},
+	}
+	for _, test := range tests {
+		node, err := ParseWithOptions(test.formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", test.formula, err)
+			continue
+		}
+		adjusted, err := AdjustReferences(node, "Sheet1", test.edit)
+		if err != nil {
+			t.Errorf("err of AdjustReferences(%s) should be nil, but %v", test.formula, err)
+		} else if formatted := Format(adjusted, FormatOptions{}); formatted != test.expected {
+			t.Errorf("AdjustReferences(%s, %v) should be %s, but %s", test.formula, test.edit, test.expected, formatted)
+		}
+	}
+}
+
+func TestAdjustReferencesInvalidEdit(t *testing.T) {
+	node, _ := Parse("A1")
+	if _, err := AdjustReferences(node, "Sheet1", SheetEdit{Type: DeleteRows, Sheet: "Sheet1", Index: 0, Count: 1}); err == nil {
+		t.Errorf("err should not be nil")
+	}
+}
@@ -0,0 +1,197 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReferenceEdge is a reference from a formula cell to another formula cell. Token is the
+// Range or Name token in the formula of From that refers To.
+type ReferenceEdge struct {
+	From  CellRef
+	To    CellRef
+	Token *Token
+}
+
+// CircularReference is a group of formula cells that refer each other (strongly connected component).
+// Chain is one concrete loop in the group that starts and ends at Cells[0].
+type CircularReference struct {
+	Cells []CellRef
+	Chain []ReferenceEdge
+}
+
+func (c CircularReference) String() string {
+	if len(c.Chain) == 0 {
+		return ""
+	}
+	parts := []string{c.Chain[0].From.String()}
+	for _, edge := range c.Chain {
+		parts = append(parts, edge.To.String())
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// CircularReferenceError is returned by Graph.RecalculationOrder when formulas have circular references.
+type CircularReferenceError struct {
+	References []CircularReference
+}
+
+func (e *CircularReferenceError) Error() string {
+	chains := make([]string, len(e.References))
+	for i, reference := range e.References {
+		chains[i] = reference.String()
+	}
+	return fmt.Sprintf("circular reference is found: %s", strings.Join(chains, ", "))
+}
+
+// CircularReferences returns all circular references in the graph. Cycles through defined
+// names are also detected. The result is sorted by the first cell of each group.
+func (g *Graph) CircularReferences() []CircularReference {
+	formulas := make([]*graphFormula, 0, len(g.formulas))
+	targets := make(map[*graphFormula]bool, len(g.formulas))
+	for _, formula := range g.formulas {
+		formulas = append(formulas, formula)
+		targets[formula] = true
+	}
+	sortFormulas(formulas)
+	edges := make(map[*graphFormula][]graphEdge, len(formulas))
+	for _, formula := range formulas {
+		edges[formula] = g.precedentEdges(formula, targets)
+	}
+	var result []CircularReference
+	for _, component := range stronglyConnectedComponents(formulas, edges) {
+		sortFormulas(component)
+		members := make(map[*graphFormula]bool, len(component))
+		for _, formula := range component {
+			members[formula] = true
+		}
+		chain := cycleChain(component[0], members, edges)
+		if chain == nil {
+			// single formula without self reference
+			continue
+		}
+		result = append(result, CircularReference{Cells: cellRefs(component), Chain: chain})
+	}
+	sortCircularReferences(result)
+	return result
+}
+
+// components splits formulas into strongly connected components. Components come after
+// the components they refer to. cyclic tells whether each component is a circular reference.
+func (g *Graph) components(formulas []*graphFormula) ([][]*graphFormula, []bool) {
+	sortFormulas(formulas)
+	targets := make(map[*graphFormula]bool, len(formulas))
+	for _, formula := range formulas {
+		targets[formula] = true
+	}
+	edges := make(map[*graphFormula][]graphEdge, len(formulas))
+	for _, formula := range formulas {
+		edges[formula] = g.precedentEdges(formula, targets)
+	}
+	components := stronglyConnectedComponents(formulas, edges)
+	cyclic := make([]bool, len(components))
+	for i, component := range components {
+		cyclic[i] = len(component) > 1
+		for _, edge := range edges[component[0]] {
+			if edge.to == component[0] {
+				// self reference like A1=A1+1
+				cyclic[i] = true
+			}
+		}
+	}
+	return components, cyclic
+}
+
+// stronglyConnectedComponents splits formulas by Tarjan's algorithm.
+func stronglyConnectedComponents(formulas []*graphFormula, edges map[*graphFormula][]graphEdge) [][]*graphFormula {
+	index := make(map[*graphFormula]int, len(formulas))
+	lowLink := make(map[*graphFormula]int, len(formulas))
+	onStack := make(map[*graphFormula]bool)
+	var stack []*graphFormula
+	var components [][]*graphFormula
+	var visit func(formula *graphFormula)
+	visit = func(formula *graphFormula) {
+		index[formula] = len(index)
+		lowLink[formula] = index[formula]
+		stack = append(stack, formula)
+		onStack[formula] = true
+		for _, edge := range edges[formula] {
+			if _, ok := index[edge.to]; !ok {
+				visit(edge.to)
+				if lowLink[edge.to] < lowLink[formula] {
+					lowLink[formula] = lowLink[edge.to]
+				}
+			} else if onStack[edge.to] && index[edge.to] < lowLink[formula] {
+				lowLink[formula] = index[edge.to]
+			}
+		}
+		if lowLink[formula] != index[formula] {
+			return
+		}
+		var component []*graphFormula
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == formula {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for _, formula := range formulas {
+		if _, ok := index[formula]; !ok {
+			visit(formula)
+		}
+	}
+	return components
+}
+
+// cycleChain finds the shortest loop from start back to start inside members by breadth first search.
+// It returns nil if there is no loop.
+func cycleChain(start *graphFormula, members map[*graphFormula]bool, edges map[*graphFormula][]graphEdge) []ReferenceEdge {
+	type step struct {
+		from *graphFormula
+		edge graphEdge
+	}
+	previous := make(map[*graphFormula]step)
+	queue := []*graphFormula{start}
+	for len(queue) > 0 {
+		formula := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges[formula] {
+			if !members[edge.to] {
+				continue
+			}
+			if edge.to == start {
+				chain := []ReferenceEdge{{From: formula.cell, To: start.cell, Token: edge.token}}
+				for current := formula; current != start; {
+					s := previous[current]
+					chain = append([]ReferenceEdge{{From: s.from.cell, To: current.cell, Token: s.edge.token}}, chain...)
+					current = s.from
+				}
+				return chain
+			}
+			if _, ok := previous[edge.to]; !ok {
+				previous[edge.to] = step{from: formula, edge: edge}
+				queue = append(queue, edge.to)
+			}
+		}
+	}
+	return nil
+}
+
+func sortCircularReferences(references []CircularReference) {
+	sort.Slice(references, func(i, j int) bool {
+		a, b := references[i].Cells[0].key(), references[j].Cells[0].key()
+		if a.Sheet != b.Sheet {
+			return a.Sheet < b.Sheet
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+}
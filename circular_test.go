@@ -0,0 +1,66 @@
+package xlsxformula
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCircularReferences(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{
+		{Sheet: "Sheet1", Cell: "A1", Formula: "B1+1"},
+		{Sheet: "Sheet1", Cell: "B1", Formula: "C1+1"},
+		{Sheet: "Sheet1", Cell: "C1", Formula: "10+A1"},
+		{Sheet: "Sheet1", Cell: "D1", Formula: "A1*2"},
+		{Sheet: "Sheet2", Cell: "A1", Formula: "SUM(A1:A3)"},
+	})
+	references := g.CircularReferences()
+	if len(references) != 2 {
+		t.Fatalf("references should be 2, but %d", len(references))
+	}
+	if actual := references[0].String(); actual != "Sheet1!A1 -> Sheet1!B1 -> Sheet1!C1 -> Sheet1!A1" {
+		t.Errorf("chain should be A1 -> B1 -> C1 -> A1, but %s", actual)
+	}
+	if len(references[0].Cells) != 3 {
+		t.Errorf("cells should be 3 (D1 is not in cycle), but %v", references[0].Cells)
+	}
+	last := references[0].Chain[2]
+	if last.Token.Text != "A1" || last.Token.Col != 4 {
+		t.Errorf("token of C1 -> A1 should be 'A1' at col 4, but '%s' at col %d", last.Token.Text, last.Token.Col)
+	}
+	if actual := references[1].String(); actual != "Sheet2!A1 -> Sheet2!A1" {
+		t.Errorf("self reference should be found, but %s", actual)
+	}
+}
+
+func TestCircularReferencesThroughName(t *testing.T) {
+	g := NewGraph()
+	g.DefineName("Total", "Subtotal*1.1")
+	g.DefineName("Subtotal", "Sheet1!$B$1")
+	g.SetFormula("Sheet1", "A1", "Total")
+	g.SetFormula("Sheet1", "B1", "A1+1")
+	references := g.CircularReferences()
+	if len(references) != 1 {
+		t.Fatalf("reference through names should be found, but %v", references)
+	}
+	if token := references[0].Chain[0].Token; token.Type != Name || token.Text != "Total" {
+		t.Errorf("edge of A1 -> B1 should be made by name Total, but %v", token)
+	}
+}
+
+func TestCircularReferenceError(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{
+		{Sheet: "Sheet1", Cell: "A1", Formula: "B1+1"},
+		{Sheet: "Sheet1", Cell: "B1", Formula: "A1+1"},
+	})
+	_, err := g.RecalculationOrder()
+	var circular *CircularReferenceError
+	if !errors.As(err, &circular) {
+		t.Fatalf("error should be CircularReferenceError, but %v", err)
+	}
+	if err.Error() != "circular reference is found: Sheet1!A1 -> Sheet1!B1 -> Sheet1!A1" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+	if len(circular.References) != 1 {
+		t.Errorf("one circular reference should be found")
+	}
+}
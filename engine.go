@@ -0,0 +1,295 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CalculationOptions are Excel's calculation settings for Engine.
+type CalculationOptions struct {
+	Iterative     bool    // calculates circular references iteratively instead of returning error
+	MaxIterations int     // maximum number of iterations (default 100)
+	MaxChange     float64 // iteration stops when all values change less than it (default 0.001)
+}
+
+// Engine recalculates formulas of a workbook incrementally. It caches the values of formula cells
+// and Recalculate evaluates only formulas affected by the changes since the last recalculation and
+// volatile formulas (NOW, RAND, OFFSET, INDIRECT...) with their dependents.
+type Engine struct {
+	options CalculationOptions
+	graph   *Graph
+	inputs  map[CellRef]Value      // constant cell values, key is CellRef.key()
+	values  map[CellRef]Value      // results of formulas, key is CellRef.key()
+	names   map[string]*Node       // defined names
+	dirty   map[CellRef]bool       // cells changed after the last recalculation
+	bounds  map[string]cellAddress // last used row and column per upper case sheet name
+}
+
+// NewEngine creates an empty Engine.
+func NewEngine(options CalculationOptions) *Engine {
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = 100
+	}
+	if options.MaxChange <= 0 {
+		options.MaxChange = 0.001
+	}
+	return &Engine{
+		options: options,
+		graph:   NewGraph(),
+		inputs:  make(map[CellRef]Value),
+		values:  make(map[CellRef]Value),
+		names:   make(map[string]*Node),
+		dirty:   make(map[CellRef]bool),
+		bounds:  make(map[string]cellAddress),
+	}
+}
+
+// Graph returns the dependency graph of the formulas.
+func (e *Engine) Graph() *Graph {
+	return e.graph
+}
+
+// SetSheetOrder sets the order of sheets for 3D references. All formulas are recalculated next time.
+func (e *Engine) SetSheetOrder(sheets []string) {
+	e.graph.SetSheetOrder(sheets)
+	for key := range e.graph.formulas {
+		e.dirty[key] = true
+	}
+}
+
+// DefineName registers a defined name like "TaxRate" that refers formula like "Sheet1!$B$1".
+// Formulas that use the name directly or through other names are recalculated next time.
+func (e *Engine) DefineName(name, formula string) error {
+	formula = strings.TrimPrefix(formula, "=")
+	node, err := ParseWithOptions(formula, ParseOptions{})
+	if err != nil {
+		return err
+	}
+	if err := e.graph.DefineName(name, formula); err != nil {
+		return err
+	}
+	e.names[strings.ToUpper(name)] = node
+	for _, referring := range e.graph.referringNames(name) {
+		for _, formula := range e.graph.nameDependents[referring] {
+			e.dirty[formula.cell.key()] = true
+		}
+	}
+	return nil
+}
+
+// SetFormula sets the formula of the cell. formula can start with "=".
+func (e *Engine) SetFormula(sheet, cell, formula string) error {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		return err
+	}
+	if err := e.graph.SetFormula(sheet, cell, formula); err != nil {
+		return err
+	}
+	key := ref.key()
+	delete(e.inputs, key)
+	e.touch(key)
+	return nil
+}
+
+// SetValue sets the constant value of the cell. It removes the formula of the cell.
+// BlankValue clears the cell.
+func (e *Engine) SetValue(sheet, cell string, value Value) error {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		return err
+	}
+	e.graph.remove(ref)
+	key := ref.key()
+	delete(e.values, key)
+	if value.Type == BlankValue {
+		delete(e.inputs, key)
+	} else {
+		e.inputs[key] = value
+	}
+	e.touch(key)
+	return nil
+}
+
+func (e *Engine) touch(key CellRef) {
+	e.dirty[key] = true
+	bound := e.bounds[key.Sheet]
+	if key.Row > bound.Row {
+		bound.Row = key.Row
+	}
+	if key.Col > bound.Col {
+		bound.Col = key.Col
+	}
+	e.bounds[key.Sheet] = bound
+}
+
+// Value returns the value of the cell. Values of formulas are the results of the last Recalculate.
+func (e *Engine) Value(sheet, cell string) (Value, error) {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		return Value{}, err
+	}
+	return e.cellValue(ref), nil
+}
+
+func (e *Engine) cellValue(ref CellRef) Value {
+	key := ref.key()
+	if _, ok := e.graph.formulas[key]; ok {
+		return e.values[key]
+	}
+	return e.inputs[key]
+}
+
+// Recalculate evaluates formulas affected by the changes and volatile formulas in the order that
+// precedents come first, and returns the recalculated cells. Without iterative calculation,
+// formulas in circular references become 0, the formulas that depend on them are calculated from it
+// and *CircularReferenceError is returned.
+func (e *Engine) Recalculate() ([]CellRef, error) {
+	var cells []CellRef
+	for key := range e.dirty {
+		cells = append(cells, key)
+	}
+	for _, formula := range e.graph.formulas {
+		if formula.volatile {
+			cells = append(cells, formula.cell)
+		}
+	}
+	targets := e.graph.allDependents(cells)
+	for _, cell := range cells {
+		if formula, ok := e.graph.formulas[cell.key()]; ok {
+			targets[formula] = true
+		}
+	}
+	e.dirty = make(map[CellRef]bool)
+	formulas := make([]*graphFormula, 0, len(targets))
+	for formula := range targets {
+		formulas = append(formulas, formula)
+	}
+	order, rest := e.graph.topologicalSort(formulas)
+	var firstErr error
+	for _, formula := range order {
+		if _, err := e.evaluate(formula); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	recalculated := cellRefs(append(order, rest...))
+	if len(rest) == 0 {
+		return recalculated, firstErr
+	}
+	if !e.options.Iterative {
+		// cells in cycles are 0 and the cells that depend on them are calculated from it
+		components, cyclic := e.graph.components(rest)
+		for i, component := range components {
+			for _, formula := range component {
+				if cyclic[i] {
+					e.values[formula.cell.key()] = NewNumber(0)
+				} else if _, err := e.evaluate(formula); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		return recalculated, &CircularReferenceError{References: e.graph.CircularReferences()}
+	}
+	for i := 0; i < e.options.MaxIterations; i++ {
+		converged := true
+		for _, formula := range rest {
+			previous := e.values[formula.cell.key()]
+			value, err := e.evaluate(formula)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if previous.Type == NumberValue && value.Type == NumberValue {
+				if math.Abs(value.Number-previous.Number) >= e.options.MaxChange {
+					converged = false
+				}
+			} else if previous.Type != value.Type || previous.String() != value.String() {
+				converged = false
+			}
+		}
+		if converged {
+			break
+		}
+	}
+	return recalculated, firstErr
+}
+
+// evaluate calculates the formula and caches the result. Evaluation error becomes #VALUE!.
+func (e *Engine) evaluate(formula *graphFormula) (Value, error) {
+	value, err := Evaluate(formula.node, &engineContext{engine: e, cell: formula.cell})
+	if err != nil {
+		value = NewError(ValueError)
+		err = fmt.Errorf("%s: %v", formula.cell.String(), err)
+	}
+	e.values[formula.cell.key()] = value
+	return value, err
+}
+
+// engineContext is EvalContext for a formula cell of Engine.
+type engineContext struct {
+	engine *Engine
+	cell   CellRef
+	names  map[string]bool // names being resolved to stop recursive definitions
+}
+
+func (c *engineContext) Position() (int, int) {
+	return c.cell.Row, c.cell.Col
+}
+
+func (c *engineContext) ResolveRange(token *Token) (Value, error) {
+	if token.Type != Range {
+		// structured references need table definitions
+		return NewError(RefError), nil
+	}
+	area, ok := tokenArea(token, c.cell.Sheet)
+	if !ok {
+		return NewError(RefError), nil
+	}
+	areas := c.engine.graph.expand([]Area{area})
+	if len(areas) == 1 && area.size() == 1 {
+		return c.engine.cellValue(CellRef{Sheet: area.Sheet, Row: area.FirstRow, Col: area.FirstCol}), nil
+	}
+	// 3D references are stacked vertically
+	var rows [][]Value
+	for _, area := range areas {
+		// whole columns and rows are cut at the last used cell
+		bound := c.engine.bounds[strings.ToUpper(area.Sheet)]
+		if area.LastRow == MaxRows {
+			area.LastRow = bound.Row
+		}
+		if area.LastCol == MaxColumns {
+			area.LastCol = bound.Col
+		}
+		if area.LastRow < area.FirstRow {
+			area.LastRow = area.FirstRow
+		}
+		if area.LastCol < area.FirstCol {
+			area.LastCol = area.FirstCol
+		}
+		for row := area.FirstRow; row <= area.LastRow; row++ {
+			values := make([]Value, 0, area.LastCol-area.FirstCol+1)
+			for col := area.FirstCol; col <= area.LastCol; col++ {
+				values = append(values, c.engine.cellValue(CellRef{Sheet: area.Sheet, Row: row, Col: col}))
+			}
+			rows = append(rows, values)
+		}
+	}
+	return NewArray(rows), nil
+}
+
+func (c *engineContext) ResolveName(token *Token) (Value, error) {
+	name := strings.ToUpper(token.Text)
+	node, ok := c.engine.names[name]
+	if !ok || token.Workbook != "" {
+		return NewError(NameError), nil
+	}
+	if c.names[name] {
+		return NewError(RefError), nil
+	}
+	if c.names == nil {
+		c.names = make(map[string]bool)
+	}
+	c.names[name] = true
+	defer delete(c.names, name)
+	return Evaluate(node, c)
+}
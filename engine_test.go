@@ -0,0 +1,139 @@
+package xlsxformula
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEngineRecalculate(t *testing.T) {
+	e := NewEngine(CalculationOptions{})
+	e.SetValue("Inputs", "B4", NewNumber(10))
+	e.SetValue("Inputs", "B5", NewNumber(1))
+	e.SetFormula("Calc", "A1", "=Inputs!B4*2")
+	e.SetFormula("Calc", "A2", "=A1+1")
+	e.SetFormula("Calc", "A3", "=Inputs!B5+1")
+	if _, err := e.Recalculate(); err != nil {
+		t.Fatalf("recalculate should succeed, but %v", err)
+	}
+	if value, _ := e.Value("Calc", "A2"); value.Number != 21 {
+		t.Errorf("Calc!A2 should be 21, but %s", value)
+	}
+	e.SetValue("Inputs", "B4", NewNumber(20))
+	cells, _ := e.Recalculate()
+	if len(cells) != 2 || cells[0].String() != "Calc!A1" || cells[1].String() != "Calc!A2" {
+		t.Errorf("only dependents of Inputs!B4 should be recalculated, but %v", cells)
+	}
+	if value, _ := e.Value("Calc", "A2"); value.Number != 41 {
+		t.Errorf("Calc!A2 should be 41, but %s", value)
+	}
+	if cells, _ := e.Recalculate(); len(cells) != 0 {
+		t.Errorf("nothing should be recalculated without changes, but %v", cells)
+	}
+}
+
+func TestEngineRangeAndName(t *testing.T) {
+	e := NewEngine(CalculationOptions{})
+	for i, cell := range []string{"A1", "A2", "A3"} {
+		e.SetValue("Sheet1", cell, NewNumber(float64(i+1)))
+	}
+	e.SetValue("Sheet1", "D1", NewNumber(0.5))
+	e.DefineName("Rate", "Sheet1!$D$1")
+	e.SetFormula("Sheet1", "B1", "SUM(A:A)*Rate")
+	e.SetFormula("Sheet1", "C1", "ROW()")
+	e.Recalculate()
+	if value, _ := e.Value("Sheet1", "B1"); value.Number != 3 {
+		t.Errorf("Sheet1!B1 should be 3, but %s", value)
+	}
+	if value, _ := e.Value("Sheet1", "C1"); value.Number != 1 {
+		t.Errorf("Sheet1!C1 should be 1, but %s", value)
+	}
+	e.SetValue("Sheet1", "D1", NewNumber(1))
+	e.Recalculate()
+	if value, _ := e.Value("Sheet1", "B1"); value.Number != 6 {
+		t.Errorf("Sheet1!B1 should follow the cell of the name, but %s", value)
+	}
+}
+
+func TestEngineRedefineName(t *testing.T) {
+	e := NewEngine(CalculationOptions{})
+	e.DefineName("Rate", "0.1")
+	e.DefineName("Fee", "5")
+	e.DefineName("Gross", "Rate+1")
+	e.SetFormula("Sheet1", "A1", "Rate*100")
+	e.SetFormula("Sheet1", "B1", "Fee*2")
+	e.SetFormula("Sheet1", "C1", "Gross*10")
+	e.Recalculate()
+	e.DefineName("Rate", "0.2")
+	cells, _ := e.Recalculate()
+	if len(cells) != 2 || cells[0].String() != "Sheet1!A1" || cells[1].String() != "Sheet1!C1" {
+		t.Errorf("only dependents of Rate should be recalculated, but %v", cells)
+	}
+	if value, _ := e.Value("Sheet1", "C1"); value.Number != 12 {
+		t.Errorf("Sheet1!C1 should be 12, but %s", value)
+	}
+}
+
+func TestEngineVolatile(t *testing.T) {
+	e := NewEngine(CalculationOptions{})
+	e.SetValue("Sheet1", "A1", NewNumber(1))
+	e.SetValue("Sheet1", "A2", NewNumber(2))
+	e.SetValue("Sheet1", "C1", NewString("A1"))
+	e.SetFormula("Sheet1", "B1", "INDIRECT(C1)")
+	e.SetFormula("Sheet1", "B2", "B1*10")
+	e.SetFormula("Sheet1", "B3", "A2*10")
+	e.Recalculate()
+	e.SetValue("Sheet1", "A1", NewNumber(5))
+	cells, _ := e.Recalculate()
+	if len(cells) != 2 {
+		t.Errorf("volatile formula and its dependent should be recalculated, but %v", cells)
+	}
+	if value, _ := e.Value("Sheet1", "B2"); value.Number != 50 {
+		t.Errorf("Sheet1!B2 should be 50, but %s", value)
+	}
+}
+
+func TestEngineCircularReference(t *testing.T) {
+	e := NewEngine(CalculationOptions{})
+	e.SetFormula("Sheet1", "A1", "B1+1")
+	e.SetFormula("Sheet1", "B1", "A1+1")
+	_, err := e.Recalculate()
+	var circular *CircularReferenceError
+	if !errors.As(err, &circular) {
+		t.Errorf("error should be CircularReferenceError, but %v", err)
+	}
+	if value, _ := e.Value("Sheet1", "A1"); value.Type != NumberValue || value.Number != 0 {
+		t.Errorf("circular formula should be 0, but %s", value)
+	}
+	// C1 and D1 depend on the cycle but are not in it
+	e = NewEngine(CalculationOptions{})
+	e.SetFormula("Sheet1", "D1", "C1*2")
+	e.SetFormula("Sheet1", "C1", "A1+10")
+	e.SetFormula("Sheet1", "A1", "B1+1")
+	e.SetFormula("Sheet1", "B1", "A1+1")
+	if _, err := e.Recalculate(); !errors.As(err, &circular) {
+		t.Errorf("error should be CircularReferenceError, but %v", err)
+	}
+	for cell, expected := range map[string]string{"A1": "0", "B1": "0", "C1": "10", "D1": "20"} {
+		if value, _ := e.Value("Sheet1", cell); value.String() != expected {
+			t.Errorf("Sheet1!%s should be %s, but %s", cell, expected, value)
+		}
+	}
+}
+
+func TestEngineIterative(t *testing.T) {
+	e := NewEngine(CalculationOptions{Iterative: true, MaxIterations: 1000, MaxChange: 0.0001})
+	// x = x / 2 + 1 converges to 2
+	e.SetFormula("Sheet1", "A1", "A1/2+1")
+	if _, err := e.Recalculate(); err != nil {
+		t.Fatalf("iterative calculation should succeed, but %v", err)
+	}
+	if value, _ := e.Value("Sheet1", "A1"); value.Number < 1.999 || value.Number > 2 {
+		t.Errorf("Sheet1!A1 should converge to 2, but %s", value)
+	}
+	e = NewEngine(CalculationOptions{Iterative: true, MaxIterations: 3})
+	e.SetFormula("Sheet1", "A1", "A1+1")
+	e.Recalculate()
+	if value, _ := e.Value("Sheet1", "A1"); value.Number != 3 {
+		t.Errorf("iteration should stop at max iterations, but %s", value)
+	}
+}
@@ -0,0 +1,288 @@
+package xlsxformula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// EvalContext resolves references in a formula to values. Implement it
+// to evaluate formulas against xlsx sheets, maps, databases and so on.
+//
+// Excel errors like #REF! should be returned as ErrorValue. The returned
+// error is for failures of the context itself and it stops the evaluation.
+type EvalContext interface {
+	// ResolveRange returns the value of a Range or StructuredRef token. A single
+	// cell should be a scalar value and an area should be an ArrayValue.
+	ResolveRange(token *Token) (Value, error)
+	// ResolveName returns the value of a Name token like a named range.
+	ResolveName(token *Token) (Value, error)
+}
+
+// Evaluate calculates the value of node. node can be a tree from ParseWithOptions
+// or flat Expression nodes from Parse. ctx can be nil if the formula has no references.
+func Evaluate(node *Node, ctx EvalContext) (Value, error) {
+	if node == nil {
+		return Value{}, errors.New("node is nil")
+	}
+	e := &evaluator{ctx: ctx}
+	return e.eval(node)
+}
+
+type evaluator struct {
+	ctx EvalContext
+}
+
+func (e *evaluator) eval(node *Node) (Value, error) {
+	switch node.Type {
+	case SingleToken:
+		return e.evalToken(node.Token)
+	case Expression:
+		if len(node.Children) == 0 {
+			return Value{}, nil
+		}
+		tree, err := buildTree(node)
+		if err != nil {
+			return Value{}, err
+		}
+		return e.eval(tree)
+	case UnaryOp:
+		operand, err := e.eval(node.Children[0])
+		if err != nil {
+			return Value{}, err
+		}
+		return unaryOperation(node.Token, operand), nil
+	case BinaryOp:
+		left, err := e.eval(node.Children[0])
+		if err != nil {
+			return Value{}, err
+		}
+		right, err := e.eval(node.Children[1])
+		if err != nil {
+			return Value{}, err
+		}
+		return binaryOperation(node.Token, left, right), nil
+	case Function:
+		return e.call(node)
+	case Intersection, RangeOp:
+		return e.resolveReference(e.reference(node))
+	case Union:
+		// multiple areas are available only as function arguments like SUM((A1,B2))
+		return NewError(ValueError), nil
+	case Array:
+		rows := make([][]Value, len(node.Children))
+		for i, row := range node.Children {
+			rows[i] = make([]Value, len(row.Children))
+			for j, element := range row.Children {
+				value, err := e.evalToken(element.Token)
+				if err != nil {
+					return Value{}, err
+				}
+				rows[i][j] = value
+			}
+		}
+		return NewArray(rows), nil
+	}
+	return Value{}, fmt.Errorf("Unknown node: %s", node.Type.String())
+}
+
+func (e *evaluator) evalToken(token *Token) (Value, error) {
+	switch token.Type {
+	case Number:
+		number, err := strconv.ParseFloat(token.Text, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("Invalid number '%s' at %d:%d", token.Text, token.Line, token.Col)
+		}
+		return NewNumber(number), nil
+	case String:
+		return NewString(token.Text), nil
+	case Bool:
+		return NewBool(token.Normalized() == "TRUE"), nil
+	case Error:
+		return NewError(errorCodes[token.Normalized()]), nil
+	case Range, StructuredRef:
+		if e.ctx == nil {
+			return NewError(RefError), nil
+		}
+		return e.ctx.ResolveRange(token)
+	case Name:
+		if e.ctx == nil {
+			return NewError(NameError), nil
+		}
+		return e.ctx.ResolveName(token)
+	}
+	return Value{}, fmt.Errorf("Unexpected token '%s' at %d:%d", token.Text, token.Line, token.Col)
+}
+
+func unaryOperation(operator *Token, operand Value) Value {
+	if operator.Text == "+" {
+		return operand
+	}
+	return lift1(operand, func(v Value) Value {
+		number, code := toNumber(v)
+		if code != "" {
+			return NewError(code)
+		}
+		if operator.Text == "%" {
+			return NewNumber(number / 100)
+		}
+		return NewNumber(-number)
+	})
+}
+
+func binaryOperation(operator *Token, left, right Value) Value {
+	return lift2(left, right, func(a, b Value) Value {
+		if operator.Type == Comparator {
+			return comparison(operator.Text, a, b)
+		}
+		if operator.Text == "&" {
+			return concatenation(a, b)
+		}
+		return arithmetic(operator.Text, a, b)
+	})
+}
+
+func arithmetic(operator string, a, b Value) Value {
+	x, code := toNumber(a)
+	if code != "" {
+		return NewError(code)
+	}
+	y, code := toNumber(b)
+	if code != "" {
+		return NewError(code)
+	}
+	switch operator {
+	case "+":
+		return numberResult(x + y)
+	case "-":
+		return numberResult(x - y)
+	case "*":
+		return numberResult(x * y)
+	case "/":
+		if y == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(x / y)
+	case "^":
+		if x == 0 && y == 0 {
+			return NewError(NumError)
+		}
+		if x == 0 && y < 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(math.Pow(x, y))
+	}
+	return NewError(ValueError)
+}
+
+func concatenation(a, b Value) Value {
+	x, code := toText(a)
+	if code != "" {
+		return NewError(code)
+	}
+	y, code := toText(b)
+	if code != "" {
+		return NewError(code)
+	}
+	return NewString(x + y)
+}
+
+func comparison(operator string, a, b Value) Value {
+	if a.Type == ErrorValue {
+		return a
+	}
+	if b.Type == ErrorValue {
+		return b
+	}
+	result := compareValues(a, b)
+	switch operator {
+	case "=":
+		return NewBool(result == 0)
+	case "<>":
+		return NewBool(result != 0)
+	case "<":
+		return NewBool(result < 0)
+	case ">":
+		return NewBool(result > 0)
+	case "<=":
+		return NewBool(result <= 0)
+	case ">=":
+		return NewBool(result >= 0)
+	}
+	return NewError(ValueError)
+}
+
+// lift1 applies f to each element if v is an array.
+func lift1(v Value, f func(Value) Value) Value {
+	if v.Type != ArrayValue {
+		return f(v)
+	}
+	rows := make([][]Value, len(v.Array))
+	for i, row := range v.Array {
+		rows[i] = make([]Value, len(row))
+		for j, cell := range row {
+			rows[i][j] = f(cell)
+		}
+	}
+	return NewArray(rows)
+}
+
+// lift2 applies f element-wise if a or b is an array. Scalars and single
+// row/column arrays are broadcast, and missing elements become #N/A like Excel.
+func lift2(a, b Value, f func(Value, Value) Value) Value {
+	if a.Type != ArrayValue && b.Type != ArrayValue {
+		return f(a, b)
+	}
+	rowsA, colsA := arraySize(a)
+	rowsB, colsB := arraySize(b)
+	rows, cols := rowsA, colsA
+	if rowsB > rows {
+		rows = rowsB
+	}
+	if colsB > cols {
+		cols = colsB
+	}
+	result := make([][]Value, rows)
+	for i := 0; i < rows; i++ {
+		result[i] = make([]Value, cols)
+		for j := 0; j < cols; j++ {
+			x, okA := broadcast(a, i, j)
+			y, okB := broadcast(b, i, j)
+			if !okA || !okB {
+				result[i][j] = NewError(NAError)
+			} else {
+				result[i][j] = f(x, y)
+			}
+		}
+	}
+	return NewArray(result)
+}
+
+// arraySize returns rows and columns of v. Scalars are 1x1.
+func arraySize(v Value) (int, int) {
+	if v.Type != ArrayValue {
+		return 1, 1
+	}
+	if len(v.Array) == 0 {
+		return 0, 0
+	}
+	return len(v.Array), len(v.Array[0])
+}
+
+func broadcast(v Value, row, col int) (Value, bool) {
+	if v.Type != ArrayValue {
+		return v, true
+	}
+	rows, cols := arraySize(v)
+	if rows == 1 {
+		row = 0
+	}
+	if cols == 1 {
+		col = 0
+	}
+	if row >= rows || col >= cols {
+		return Value{}, false
+	}
+	return v.Array[row][col], true
+}
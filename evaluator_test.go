@@ -0,0 +1,280 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+type mapContext map[string]Value
+
+func (m mapContext) ResolveRange(token *Token) (Value, error) {
+	if value, ok := m[token.SheetPrefix()+token.Normalized()]; ok {
+		return value, nil
+	}
+	return Value{}, nil
+}
+
+func (m mapContext) ResolveName(token *Token) (Value, error) {
+	if value, ok := m[token.SheetPrefix()+token.Text]; ok {
+		return value, nil
+	}
+	return NewError(NameError), nil
+}
+
+func evaluateString(t *testing.T, formula string, ctx EvalContext) string {
+	node, err := ParseWithOptions(formula, ParseOptions{})
+	if err != nil {
+		t.Errorf("parse error of %s: %v", formula, err)
+		return ""
+	}
+	value, err := Evaluate(node, ctx)
+	if err != nil {
+		t.Errorf("evaluate error of %s: %v", formula, err)
+		return ""
+	}
+	return value.String()
+}
+
+func TestEvaluateArithmetic(t *testing.T) {
+	if result := evaluateString(t, "1 + 2 * 3 - 4 / 8", nil); result != "6.5" {
+		t.Errorf("result should be 6.5, but %s", result)
+	}
+	if result := evaluateString(t, "-2 ^ 2", nil); result != "4" {
+		t.Errorf("result should be 4, but %s", result)
+	}
+	if result := evaluateString(t, "0.1 + 0.2", nil); result != "0.3" {
+		t.Errorf("result should be 0.3, but %s", result)
+	}
+}
+
+func TestEvaluateFlatExpression(t *testing.T) {
+	node, err := Parse("(1 + 2) * 3 ^ 2")
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	}
+	value, err := Evaluate(node, nil)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if value.Type != NumberValue || value.Number != 27 {
+		t.Errorf("result should be 27, but %s", value.String())
+	}
+}
+
+func TestEvaluateCoercion(t *testing.T) {
+	if result := evaluateString(t, `"1" + "2"`, nil); result != "3" {
+		t.Errorf("result should be 3, but %s", result)
+	}
+	if result := evaluateString(t, `TRUE + TRUE`, nil); result != "2" {
+		t.Errorf("result should be 2, but %s", result)
+	}
+	if result := evaluateString(t, `"a" + 1`, nil); result != "#VALUE!" {
+		t.Errorf("result should be #VALUE!, but %s", result)
+	}
+	if result := evaluateString(t, `"a" & 1.5 & TRUE & A1`, mapContext{}); result != "a1.5TRUE" {
+		t.Errorf("result should be a1.5TRUE, but %s", result)
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	ctx := mapContext{"A1": NewError(NAError)}
+	if result := evaluateString(t, "1 / 0", ctx); result != "#DIV/0!" {
+		t.Errorf("result should be #DIV/0!, but %s", result)
+	}
+	if result := evaluateString(t, "1 / 0 + A1", ctx); result != "#DIV/0!" {
+		t.Errorf("left error should win, but %s", result)
+	}
+	if result := evaluateString(t, `A1 = "x"`, ctx); result != "#N/A" {
+		t.Errorf("result should be #N/A, but %s", result)
+	}
+	if result := evaluateString(t, "UNKNOWN", ctx); result != "#NAME?" {
+		t.Errorf("result should be #NAME?, but %s", result)
+	}
+	if result := evaluateString(t, "1 + Sheet1!#REF!", ctx); result != "#REF!" {
+		t.Errorf("result should be #REF!, but %s", result)
+	}
+	if result := evaluateString(t, "IFERROR(#N/A, 0)", ctx); result != "0" {
+		t.Errorf("result should be 0, but %s", result)
+	}
+}
+
+func TestEvaluateComparison(t *testing.T) {
+	ctx := mapContext{}
+	tests := map[string]string{
+		`"abc" = "ABC"`:  "TRUE",
+		`1 < "a"`:        "TRUE",
+		`"z" < FALSE`:    "TRUE",
+		`FALSE < TRUE`:   "TRUE",
+		`A1 = 0`:         "TRUE",
+		`A1 = ""`:        "TRUE",
+		`A1 = FALSE`:     "TRUE",
+		`2 >= 10`:        "FALSE",
+		`"b" <> "a"`:     "TRUE",
+		`1 + 1 = 2 & ""`: "FALSE",
+	}
+	for formula, expected := range tests {
+		if result := evaluateString(t, formula, ctx); result != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, result)
+		}
+	}
+}
+
+func TestEvaluateArray(t *testing.T) {
+	ctx := mapContext{
+		"A1:A2": NewArray([][]Value{{NewNumber(1)}, {NewNumber(2)}}),
+		"B1:C1": NewArray([][]Value{{NewNumber(10), NewNumber(20)}}),
+	}
+	if result := evaluateString(t, "A1:A2 * 2", ctx); result != "{2;4}" {
+		t.Errorf("result should be {2;4}, but %s", result)
+	}
+	if result := evaluateString(t, "A1:A2 + B1:C1", ctx); result != "{11,21;12,22}" {
+		t.Errorf("result should be {11,21;12,22}, but %s", result)
+	}
+	if result := evaluateString(t, `{1,"a";TRUE,#N/A} & "!"`, ctx); result != `{"1!","a!";"TRUE!",#N/A}` {
+		t.Errorf(`result should be {"1!","a!";"TRUE!",#N/A}, but %s`, result)
+	}
+	if result := evaluateString(t, "{1,2} * {-1;10}", ctx); result != "{-1,-2;10,20}" {
+		t.Errorf("result should be {-1,-2;10,20}, but %s", result)
+	}
+}
+
+func TestEvaluateIntersectionAndUnion(t *testing.T) {
+	ctx := mapContext{
+		"B2:C3": NewArray([][]Value{{NewNumber(1), NewNumber(2)}, {NewNumber(3), NewNumber(4)}}),
+		"C3":    NewNumber(4),
+		"A1":    NewNumber(10),
+		"E5":    NewNumber(100),
+	}
+	if result := evaluateString(t, "SUM(A1:C3 B2:D4)", ctx); result != "10" {
+		t.Errorf("result should be 10, but %s", result)
+	}
+	if result := evaluateString(t, "A1:C3 C3:D4 * 2", ctx); result != "8" {
+		t.Errorf("result should be 8, but %s", result)
+	}
+	if result := evaluateString(t, "A1:B2 C3:D4", ctx); result != "#NULL!" {
+		t.Errorf("result should be #NULL!, but %s", result)
+	}
+	if result := evaluateString(t, "SUM((A1,E5),1)", ctx); result != "111" {
+		t.Errorf("result should be 111, but %s", result)
+	}
+	if result := evaluateString(t, "AREAS((A1,E5,B2:C3))", ctx); result != "3" {
+		t.Errorf("result should be 3, but %s", result)
+	}
+}
+
+func TestEvaluateUnionArgument(t *testing.T) {
+	ctx := mapContext{
+		"A1:A3": column(NewNumber(1), NewNumber(2), NewNumber(3)),
+		"B1:B3": column(NewNumber(4), NewNumber(5), NewNumber(6)),
+		"B2":    NewNumber(5),
+	}
+	tests := map[string]string{
+		"LARGE((A1:A3,B1:B3),2)":        "5",
+		"SMALL((A1:A3,B1:B3),1)":        "1",
+		"RANK(5,(A1:A3,B1:B3))":         "2",
+		"MEDIAN((A1:A3,B1:B3))":         "3.5",
+		"PERCENTILE((A1:A3,B1:B3),0.5)": "3.5",
+		"COUNT((A1:A3,B1:B3),1)":        "7",
+		"INDEX((A1:A3,B1:B3),2,1,2)":    "5",
+		"INDEX((A1:A3,B1:B3),2,1,3)":    "#REF!",
+		"INDEX(A1:A3,2,1,2)":            "#REF!",
+		"COUNTIF((A1:A3,B1:B3),1)":      "#VALUE!",
+		"ABS((A1:A3,B1:B3))":            "#VALUE!",
+	}
+	for formula, expected := range tests {
+		if result := evaluateString(t, formula, ctx); result != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, result)
+		}
+	}
+}
+
+func TestEvaluateRangeOperator(t *testing.T) {
+	ctx := mapContext{
+		"A1:B5":        NewArray([][]Value{{NewNumber(1), NewNumber(1)}, {NewNumber(1), NewNumber(1)}, {NewNumber(1), NewNumber(1)}, {NewNumber(1), NewNumber(1)}, {NewNumber(1), NewNumber(1)}}),
+		"A1:B2":        NewArray([][]Value{{NewNumber(1), NewNumber(1)}, {NewNumber(1), NewNumber(1)}}),
+		"Sheet1!A1:B2": NewArray([][]Value{{NewNumber(2), NewNumber(2)}, {NewNumber(2), NewNumber(2)}}),
+		"Name1":        NewNumber(1),
+		"Name2":        NewNumber(2),
+	}
+	if result := evaluateString(t, "SUM(A1:INDEX(B1:B10,5))", ctx); result != "10" {
+		t.Errorf("result should be 10, but %s", result)
+	}
+	if result := evaluateString(t, "SUM(OFFSET(A1,1,1):A1)", ctx); result != "4" {
+		t.Errorf("result should be 4, but %s", result)
+	}
+	if result := evaluateString(t, "SUM(Sheet1!B2:Sheet1!A1)", ctx); result != "8" {
+		t.Errorf("result should be 8, but %s", result)
+	}
+	if result := evaluateString(t, "SUM(Sheet1!A1:Sheet2!B2)", ctx); result != "#VALUE!" {
+		t.Errorf("result should be #VALUE!, but %s", result)
+	}
+	// defined names are not resolved to references
+	for _, formula := range []string{"SUM(Name1:Name2)", "SUM(Name1:A1)", "Name1 A1"} {
+		if result := evaluateString(t, formula, ctx); result != "#VALUE!" {
+			t.Errorf("%s should be #VALUE!, but %s", formula, result)
+		}
+	}
+	for _, formula := range []string{"SUM(OFFSET():A1)", "SUM(INDEX():A1)", "SUM(A1:INDIRECT())", "OFFSET(A1):B2 A1"} {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("parse error of %s: %v", formula, err)
+			continue
+		}
+		if _, err := Evaluate(node, ctx); err == nil {
+			t.Errorf("%s should be error, but nil", formula)
+		}
+	}
+}
+
+func TestEvaluateWholeColumnAndRow(t *testing.T) {
+	ctx := mapContext{
+		"A:A":      NewArray([][]Value{{NewNumber(1)}, {NewNumber(2)}}),
+		"Data!2:2": NewArray([][]Value{{NewNumber(3), NewNumber(4)}}),
+		"B5":       NewNumber(10),
+		"A2:A3":    NewArray([][]Value{{NewNumber(2)}, {NewNumber(5)}}),
+	}
+	if result := evaluateString(t, "SUM(A:A) + SUM(Data!2:2)", ctx); result != "10" {
+		t.Errorf("result should be 10, but %s", result)
+	}
+	if result := evaluateString(t, "INDEX(B:B, 5)", mapContext{"B:B": NewArray([][]Value{{NewNumber(1)}, {NewNumber(2)}, {NewNumber(3)}, {NewNumber(4)}, {NewNumber(10)}})}); result != "10" {
+		t.Errorf("result should be 10, but %s", result)
+	}
+	if result := evaluateString(t, "A:A 5:5 + B:B B5", ctx); result != "10" {
+		t.Errorf("result should be 10, but %s", result)
+	}
+	if result := evaluateString(t, "SUM(A2:INDEX(A:A, 3))", ctx); result != "7" {
+		t.Errorf("result should be 7, but %s", result)
+	}
+	if result := evaluateString(t, "COLUMN(C:E)", ctx); result != "{3,4,5}" {
+		t.Errorf("result should be {3,4,5}, but %s", result)
+	}
+}
+
+func TestEvaluateEscapedString(t *testing.T) {
+	if result := evaluateString(t, `LEN("He said ""hi""") & "|" & "a""" & "b"`, mapContext{}); result != `12|a"b` {
+		t.Errorf(`result should be 12|a"b, but %s`, result)
+	}
+}
+
+func TestEvaluatePercentAndScientificNotation(t *testing.T) {
+	tests := map[string]string{
+		"50%*4":       "2",
+		"-5%^2":       "0.0025",
+		"2^200%":      "4",
+		"1.5E+3+.5":   "1500.5",
+		"1E-2*A1%":    "0.001",
+		"SUM({1,2})%": "0.03",
+	}
+	ctx := mapContext{"A1": NewNumber(10)}
+	for formula, expected := range tests {
+		if result := evaluateString(t, formula, ctx); result != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, result)
+		}
+	}
+}
+
+func TestEvaluateCaseInsensitive(t *testing.T) {
+	ctx := mapContext{"A1": NewNumber(2)}
+	if result := evaluateString(t, `if(true,sum({1,2},a1),0) & iserror(#n/a) & not(false)`, ctx); result != "5TRUETRUE" {
+		t.Errorf("result should be 5TRUETRUE, but %s", result)
+	}
+}
@@ -0,0 +1,271 @@
+package xlsxformula
+
+import (
+	"bytes"
+	"strings"
+)
+
+// FormatOptions controls the output of Format.
+type FormatOptions struct {
+	// Equal adds "=" at the head of the formula.
+	Equal bool
+	// Spaces adds spaces around binary operators and after commas like "1 + SUM(A1, B1)".
+	Spaces bool
+	// Separators writes the formula with the argument, decimal and array separators of the locale
+	// like "SUMME(A1;0,5)". The zero value is the invariant (en-US) ones.
+	Separators Separators
+}
+
+// unaryPrecedence is bigger than arithmetic operators and percent, and less than reference operators.
+// Operands have the biggest one.
+const (
+	unaryPrecedence   = 7
+	operandPrecedence = 11
+)
+
+// Format serializes node into an Excel formula. node can be a tree from
+// ParseWithOptions or flat Expression nodes from Parse. Parentheses are
+// added only where the precedence requires them.
+func Format(node *Node, options FormatOptions) string {
+	var buffer bytes.Buffer
+	if options.Equal {
+		buffer.WriteByte('=')
+	}
+	options.Separators = options.Separators.normalize()
+	f := &formatter{buffer: &buffer, options: options}
+	f.format(node)
+	return buffer.String()
+}
+
+// FormatTokens writes tokens back into a formula. Tokens from TokenizeOptions{Trivia: true}
+// reproduce the original formula byte for byte with spaces and line breaks. Tokens edited
+// after tokenizing (e.g. Sheet is renamed) are written in the canonical form.
+func FormatTokens(tokens []*Token) string {
+	var buffer bytes.Buffer
+	for _, token := range tokens {
+		buffer.WriteString(token.Leading)
+		text := formatToken(token)
+		if token.source != nil && token.source.formatted == text {
+			text = token.source.raw
+		}
+		buffer.WriteString(text)
+		buffer.WriteString(token.Trailing)
+	}
+	return buffer.String()
+}
+
+type formatter struct {
+	buffer  *bytes.Buffer
+	options FormatOptions
+}
+
+func (f *formatter) format(node *Node) {
+	switch node.Type {
+	case SingleToken:
+		f.buffer.WriteString(f.options.Separators.localize(node.Token, 0))
+	case Expression:
+		if len(node.Children) == 0 {
+			// missing argument
+			return
+		}
+		tree, err := buildTree(node)
+		if err != nil {
+			for i, child := range node.Children {
+				if i != 0 && f.options.Spaces {
+					f.buffer.WriteByte(' ')
+				}
+				f.format(child)
+			}
+			return
+		}
+		f.format(tree)
+	case Function:
+		f.buffer.WriteString(node.Token.SheetPrefix() + strings.ToUpper(node.Token.Text))
+		f.buffer.WriteByte('(')
+		for i, child := range node.Children {
+			if i != 0 {
+				f.comma()
+			}
+			f.format(child)
+		}
+		f.buffer.WriteByte(')')
+	case BinaryOp:
+		precedence := binaryPrecedence[node.Token.Text]
+		// binary operators are left associative: the right operand needs parentheses at the same precedence
+		f.operand(node.Children[0], precedence)
+		if f.options.Spaces {
+			f.buffer.WriteByte(' ')
+			f.buffer.WriteString(node.Token.Text)
+			f.buffer.WriteByte(' ')
+		} else if (node.Token.Text == "+" || node.Token.Text == "-") && f.endsWithExponent() {
+			// a name like 1E before +1 would be read as the number 1E+1
+			f.buffer.WriteString(" " + node.Token.Text)
+		} else {
+			f.buffer.WriteString(node.Token.Text)
+		}
+		f.operand(node.Children[1], precedence+1)
+	case UnaryOp:
+		if node.Token.Text == "%" {
+			f.operand(node.Children[0], percentPrecedence)
+			f.buffer.WriteByte('%')
+		} else {
+			f.buffer.WriteString(node.Token.Text)
+			f.operand(node.Children[0], unaryPrecedence)
+		}
+	case RangeOp:
+		precedence := binaryPrecedence[":"]
+		f.operand(node.Children[0], precedence)
+		left, right := node.Children[0], node.Children[1]
+		if last := lastToken(left); last != nil && right.Type == SingleToken && merges(last, right.Token) {
+			// names A and R become the column range A:R without space
+			f.buffer.WriteByte(' ')
+		}
+		f.buffer.WriteByte(':')
+		if left.Type == SingleToken && left.Token.Type == Name && left.Token.Sheet == "" &&
+			right.Type == SingleToken && right.Token.Sheet != "" && right.Token.LastSheet == "" && right.Token.Workbook == "" {
+			// Name1:'Sheet1'!A1 needs quotes not to be a 3D reference
+			f.buffer.WriteString("'" + strings.Replace(right.Token.Sheet, "'", "''", -1) + "'!" + tokenText(right.Token))
+		} else {
+			f.operand(right, precedence+1)
+		}
+	case Intersection:
+		precedence := binaryPrecedence[" "]
+		f.intersectionOperand(node.Children[0], precedence)
+		f.buffer.WriteByte(' ')
+		f.intersectionOperand(node.Children[1], precedence+1)
+	case Union:
+		// union always needs parentheses
+		f.buffer.WriteByte('(')
+		for i, child := range node.Children {
+			if i != 0 {
+				f.buffer.WriteRune(f.options.Separators.Argument)
+			}
+			f.operand(child, binaryPrecedence[","]+1)
+		}
+		f.buffer.WriteByte(')')
+	case Array:
+		f.buffer.WriteByte('{')
+		for i, row := range node.Children {
+			if i != 0 {
+				f.buffer.WriteRune(f.options.Separators.ArrayRow)
+			}
+			for j, element := range row.Children {
+				if j != 0 {
+					f.buffer.WriteRune(f.options.Separators.ArrayColumn)
+				}
+				f.buffer.WriteString(f.options.Separators.localize(element.Token, 1))
+			}
+		}
+		f.buffer.WriteByte('}')
+	}
+}
+
+// operand writes node with parentheses if its precedence is less than minPrecedence.
+func (f *formatter) operand(node *Node, minPrecedence int) {
+	if node.Type == Expression && len(node.Children) > 0 {
+		if tree, err := buildTree(node); err == nil {
+			node = tree
+		}
+	}
+	if precedenceOf(node) < minPrecedence {
+		f.buffer.WriteByte('(')
+		f.format(node)
+		f.buffer.WriteByte(')')
+	} else {
+		f.format(node)
+	}
+}
+
+// lastToken returns the token at the end of the formatted operand of a range operator.
+// It returns nil if the operand ends with a parenthesis.
+func lastToken(node *Node) *Token {
+	if node.Type == RangeOp {
+		node = node.Children[1]
+	}
+	if node.Type == SingleToken && (node.Token.Type == Name || node.Token.Type == Range) {
+		return node.Token
+	}
+	return nil
+}
+
+// merges returns true if the lexer reads left:right as a single token like A:R of names A and R.
+func merges(left, right *Token) bool {
+	tokens, err := Tokenize(formatToken(left) + ":" + formatToken(right))
+	return err == nil && len(tokens) == 1
+}
+
+// lastWord returns the word at the end of the buffer that the lexer reads with the next text.
+func (f *formatter) lastWord() string {
+	text := f.buffer.String()
+	head := strings.LastIndexFunc(text, func(r rune) bool {
+		return symbolSeparator[r]
+	})
+	return text[head+1:]
+}
+
+// endsWithExponent returns true if the last word in the buffer looks like the head of a number in
+// scientific notation like "1E".
+func (f *formatter) endsWithExponent() bool {
+	return exponentPattern.MatchString(f.lastWord())
+}
+
+// intersectionOperand keeps parentheses around constants like (0) because the space between
+// a constant and a reference is not the intersection operator.
+func (f *formatter) intersectionOperand(node *Node, minPrecedence int) {
+	unwrapped := node
+	if node.Type == Expression && len(node.Children) > 0 {
+		if tree, err := buildTree(node); err == nil {
+			unwrapped = tree
+		}
+	}
+	if unwrapped.Type == SingleToken && unwrapped.Token.Type != Range && unwrapped.Token.Type != Name && unwrapped.Token.Type != StructuredRef {
+		f.buffer.WriteByte('(')
+		f.format(unwrapped)
+		f.buffer.WriteByte(')')
+		return
+	}
+	f.operand(node, minPrecedence)
+}
+
+func (f *formatter) comma() {
+	f.buffer.WriteRune(f.options.Separators.Argument)
+	if f.options.Spaces {
+		f.buffer.WriteByte(' ')
+	}
+}
+
+func precedenceOf(node *Node) int {
+	switch node.Type {
+	case BinaryOp:
+		return binaryPrecedence[node.Token.Text]
+	case UnaryOp:
+		if node.Token.Text == "%" {
+			return percentPrecedence
+		}
+		return unaryPrecedence
+	case Intersection:
+		return binaryPrecedence[" "]
+	case RangeOp:
+		return binaryPrecedence[":"]
+	}
+	return operandPrecedence
+}
+
+func formatToken(token *Token) string {
+	switch token.Type {
+	case String:
+		return `"` + strings.Replace(token.Text, `"`, `""`, -1) + `"`
+	case Range, Name, Error:
+		return token.SheetPrefix() + tokenText(token)
+	}
+	return tokenText(token)
+}
+
+// tokenText returns the text of the token in upper case like Excel shows it (a1 is A1 and true is TRUE).
+// Defined names keep the case.
+func tokenText(token *Token) string {
+	if token.Type == Name {
+		return token.Text
+	}
+	return token.Normalized()
+}
@@ -0,0 +1,159 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+// formulas used in the other tests
+var formatCorpus []string = []string{
+	"10",
+	"-10",
+	"10 + 20",
+	"((((10+20))))",
+	"(((10)))",
+	"(1 + 2) * 3 ^ 2",
+	"10 * - -10",
+	"10 + 20 / 40 * 50 ^ 2",
+	"2 ^ 3 ^ 2 - 1 - 1",
+	"2 ^ (3 ^ 2) - (1 - 1)",
+	"-2 ^ 2",
+	"-(2 ^ 2)",
+	"2 ^ -1",
+	"IF()",
+	"TODAY()",
+	`FALSE <> "20"`,
+	`IF(10 < E2, "bigger", "smaller")`,
+	`IF(A1,,2)`,
+	"(1 + 2) * SUM(3 - 4 * 5, -A1)",
+	`"a" & 1 + 2 = "a3"`,
+	`("a" & 1) + 2 = ("a3" = TRUE)`,
+	" 10.5 - 20.6 * 30.1 / 40.4",
+	`"hello"&"world"`,
+	`1 <= 10`,
+	`A1 ^ VARIABLE`,
+	`LOG10($A$1)`,
+	`SUM(Sales[Q1 Amount])*2`,
+	`Sheet2!A1 + 'Q1 Data'!B3:C4 + 'It''s'!Total + Sheet1:Sheet3!$A$1`,
+	`#N/A+#DIV/0!*Sheet1!#REF!&#NAME?`,
+	`INDEX({"a","b";"c","d"}, 2, 1) & SUM({1,-2,3} * 2)`,
+	`A1:C3 B2:D4`,
+	`SUM((A1, B2:C3), D4) + -A1 B1`,
+	`(A1 B1,C1) Sheet1!D1:E4`,
+	`SUM(A1:INDEX(B1:B10, 5), OFFSET(A1, 1, 1):C10, Name1:Name2)`,
+	`Sheet1!A1:'My Sheet'!B2 + (A1:B2):C3`,
+	`SUM(A1:B2:C3, A:B:C, A1:B2:C3:D4, CB1 :B1, c :bbu :c, E!B1 :t)`,
+	`SUM(A:A, $B:$D, 1:1, Sheet1!$3:$5) + INDEX(B:B, 5)`,
+	`[1]Sheet1!A1 + '[Book 1]My Sheet'!B2:C3 + 'C:\Data\[Book1.xlsx]Sheet1'!A1 + [1]!TaxRate`,
+	`SUM(Start :Finish, A :R, (1) A1)`,
+	`"He said ""hi""" & """" & ""`,
+	`-5%^2 + 2^-50% - (1+2)% + -(5%) + 1.5E+10 * .5 + 1e-3`,
+	`{1,"a";TRUE,#N/A}`,
+}
+
+func equalNode(a, b *Node) bool {
+	if a.Type != b.Type || len(a.Children) != len(b.Children) || (a.Token == nil) != (b.Token == nil) {
+		return false
+	}
+	if a.Token != nil && (a.Token.Type != b.Token.Type || a.Token.Text != b.Token.Text || a.Token.Workbook != b.Token.Workbook || a.Token.Sheet != b.Token.Sheet || a.Token.LastSheet != b.Token.LastSheet) {
+		return false
+	}
+	for i := range a.Children {
+		if !equalNode(a.Children[i], b.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, formula := range formatCorpus {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		for _, options := range []FormatOptions{{}, {Spaces: true}} {
+			formatted := Format(node, options)
+			reparsed, err := ParseWithOptions(formatted, ParseOptions{})
+			if err != nil {
+				t.Errorf("formatted %s (from %s) should be parsed, but %v", formatted, formula, err)
+			} else if !equalNode(node, reparsed) {
+				t.Errorf("%s should have the same structure as %s, but %s", formatted, node.String(), reparsed.String())
+			}
+		}
+		flat, _ := Parse(formula)
+		if formatted := Format(flat, FormatOptions{}); formatted != Format(node, FormatOptions{}) {
+			t.Errorf("flat and tree nodes of %s should be formatted in the same way, but %s and %s", formula, formatted, Format(node, FormatOptions{}))
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"((((10+20))))":                    "10+20",
+		"(1 + 2) * 3 ^ 2":                  "(1+2)*3^2",
+		"2 ^ (3 ^ 2) - (1 - 1)":            "2^(3^2)-(1-1)",
+		"(2 ^ 3) ^ 2 - 1 - 1":              "2^3^2-1-1",
+		"-(2 ^ 2)":                         "-(2^2)",
+		"10 * - -10":                       "10*--10",
+		`IF(A1,,"hi")`:                     `IF(A1,,"hi")`,
+		`SUM( $A$1:B$2 , 'Q1 Data'!C3 )`:   `SUM($A$1:B$2,'Q1 Data'!C3)`,
+		`{1,-2;"a",TRUE}`:                  `{1,-2;"a",TRUE}`,
+		`if(true,sum(a1:b2),#n/a)*TaxRate`: `IF(TRUE,SUM(A1:B2),#N/A)*TaxRate`,
+		"A1:B2:C3":                         "A1:B2:C3",
+		"A:B:C":                            "A:B:C",
+		"A1:B2:C3:D4":                      "A1:B2:C3:D4",
+		"Sheet1!A :R":                      "Sheet1!A :R",
+	}
+	for formula, expected := range tests {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		if formatted := Format(node, FormatOptions{}); formatted != expected {
+			t.Errorf("Format(%s) should be %s, but %s", formula, expected, formatted)
+		}
+	}
+	node := &Node{Type: SingleToken, Token: &Token{Type: String, Text: `say "hi"`}}
+	if formatted := Format(node, FormatOptions{}); formatted != `"say ""hi"""` {
+		t.Errorf(`double quotation should be escaped, but %s`, formatted)
+	}
+	node, _ = ParseWithOptions("1+SUM(A1,B1)", ParseOptions{})
+	if formatted := Format(node, FormatOptions{Equal: true, Spaces: true}); formatted != "=1 + SUM(A1, B1)" {
+		t.Errorf("Format() should be =1 + SUM(A1, B1), but %s", formatted)
+	}
+}
+
+func TestFormatTokensLossless(t *testing.T) {
+	formulas := []string{
+		"=IF(A1>0,\r\n\tIF(B1>0, \"both\",\n\t\t\"A1\"),\r\n\t'Sheet1'!C1 )  ",
+		" SUM( Sheet1:Sheet3!A1 , 'My Sheet'!$B$2:B3 ) \n",
+		"Sales[[#This Row],[Amount]] * {1,2;3,4}",
+		"#REF! + Sheet1!#REF!",
+	}
+	for _, formula := range formulas {
+		tokens, err := TokenizeWithOptions(formula, TokenizeOptions{Trivia: true})
+		if err != nil {
+			t.Errorf("%q: err should be nil, but %v", formula, err)
+			continue
+		}
+		if actual := FormatTokens(tokens); actual != formula {
+			t.Errorf("FormatTokens() should be %q, but %q", formula, actual)
+		}
+	}
+}
+
+func TestFormatTokensAfterEdit(t *testing.T) {
+	formula := "=IF(\n  'Sheet1'!A1 > 0,\n  Sheet1!B1,\n  'Other'!C1\n)"
+	tokens, _ := TokenizeWithOptions(formula, TokenizeOptions{Trivia: true})
+	for _, token := range tokens {
+		if token.Sheet == "Sheet1" {
+			token.Sheet = "Input Data"
+		}
+	}
+	expected := "=IF(\n  'Input Data'!A1 > 0,\n  'Input Data'!B1,\n  'Other'!C1\n)"
+	if actual := FormatTokens(tokens); actual != expected {
+		t.Errorf("FormatTokens() should be %q, but %q", expected, actual)
+	}
+}
@@ -0,0 +1,520 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PositionContext is an optional interface of EvalContext. ROW() and COLUMN()
+// without arguments use it to know the cell that has the formula.
+type PositionContext interface {
+	// Position returns 1 origin row and column of the current cell.
+	Position() (row, col int)
+}
+
+type functionSpec struct {
+	minArgs int
+	maxArgs int // -1 means unlimited
+	// volatile functions like NOW() return a different value each time.
+	volatile bool
+	// reference functions like OFFSET() return a reference. It is passed to other
+	// functions in the same way as Range tokens.
+	reference bool
+	// scalar functions are applied element-wise when an argument is an array.
+	scalar bool
+	// unions functions accept multiple areas like SUM((A1:A3,C1:C3)). Other functions
+	// return #VALUE! for them like Excel.
+	unions bool
+	// user functions are registered by RegisterFunction. They have no localized names.
+	user bool
+	// call receives evaluated arguments. References are passed as ArrayValue.
+	call func(args *arguments) Value
+	// lazy receives unevaluated arguments. It is used by IF() and so on.
+	lazy func(e *evaluator, args []*Node) (Value, error)
+}
+
+var functionsLock sync.RWMutex
+var functions map[string]*functionSpec = map[string]*functionSpec{}
+
+// RegisterFunction registers a user-defined function. maxArgs -1 means any number of arguments.
+// It replaces the builtin function that has the same name. Arguments are evaluated before
+// calling fn and references are passed as ArrayValue even if it is a single cell.
+// Multiple areas like (A1:A3,C1:C3) are passed as a single column ArrayValue.
+func RegisterFunction(name string, minArgs, maxArgs int, fn func(args []Value) Value) {
+	registerFunction(name, &functionSpec{
+		minArgs: minArgs,
+		maxArgs: maxArgs,
+		unions:  true,
+		user:    true,
+		call: func(args *arguments) Value {
+			return fn(args.values)
+		},
+	})
+}
+
+// IsFunction returns true if a function is registered with the name.
+func IsFunction(name string) bool {
+	return lookupFunction(name) != nil
+}
+
+// IsVolatileFunction returns true if the function returns a different value in each
+// calculation like NOW(), RAND(), OFFSET() and INDIRECT().
+func IsVolatileFunction(name string) bool {
+	spec := lookupFunction(name)
+	return spec != nil && spec.volatile
+}
+
+func registerFunction(name string, spec *functionSpec) {
+	functionsLock.Lock()
+	defer functionsLock.Unlock()
+	functions[strings.ToUpper(name)] = spec
+}
+
+func lookupFunction(name string) *functionSpec {
+	name = strings.ToUpper(name)
+	// newer functions are stored with the prefix in xlsx files
+	name = strings.TrimPrefix(name, "_XLFN.")
+	name = strings.TrimPrefix(name, "_XLWS.")
+	functionsLock.RLock()
+	defer functionsLock.RUnlock()
+	return functions[name]
+}
+
+// registerScalar registers a function that is applied element-wise to array arguments.
+func registerScalar(name string, minArgs, maxArgs int, call func(args *arguments) Value) {
+	registerFunction(name, &functionSpec{minArgs: minArgs, maxArgs: maxArgs, scalar: true, call: call})
+}
+
+// registerArray registers a function that receives array arguments as they are.
+func registerArray(name string, minArgs, maxArgs int, call func(args *arguments) Value) {
+	registerFunction(name, &functionSpec{minArgs: minArgs, maxArgs: maxArgs, call: call})
+}
+
+// registerAggregate registers an array function that also accepts multiple areas like LARGE((A1:A3,C1:C3),2).
+func registerAggregate(name string, minArgs, maxArgs int, call func(args *arguments) Value) {
+	registerFunction(name, &functionSpec{minArgs: minArgs, maxArgs: maxArgs, unions: true, call: call})
+}
+
+func registerLazy(name string, minArgs, maxArgs int, lazy func(e *evaluator, args []*Node) (Value, error)) {
+	registerFunction(name, &functionSpec{minArgs: minArgs, maxArgs: maxArgs, lazy: lazy})
+}
+
+func (e *evaluator) call(node *Node) (Value, error) {
+	spec := lookupFunction(node.Token.Text)
+	if spec == nil {
+		return NewError(NameError), nil
+	}
+	if err := checkArgumentCount(node, spec); err != nil {
+		return Value{}, err
+	}
+	if spec.lazy != nil {
+		return spec.lazy(e, node.Children)
+	}
+	for _, child := range node.Children {
+		if child.Type == Union && !spec.unions {
+			// COUNTIF((A1:A3,C1:C3),1) is #VALUE!
+			return NewError(ValueError), nil
+		}
+	}
+	values, err := e.evalArguments(node.Children)
+	if err != nil {
+		return Value{}, err
+	}
+	if spec.scalar {
+		return liftCall(values, spec.call), nil
+	}
+	return spec.call(&arguments{values: values}), nil
+}
+
+// checkArgumentCount returns error if the function node has too few or too many arguments for spec.
+func checkArgumentCount(node *Node, spec *functionSpec) error {
+	if len(node.Children) < spec.minArgs || (spec.maxArgs >= 0 && len(node.Children) > spec.maxArgs) {
+		return fmt.Errorf("Function %s at %d:%d has wrong number of arguments: %d", node.Token.Text, node.Token.Line, node.Token.Col, len(node.Children))
+	}
+	return nil
+}
+
+// evalArguments evaluates function arguments. The areas of a union like (A1:A3,C1:C3)
+// are joined into a single column array so that the positions of the other arguments are kept.
+func (e *evaluator) evalArguments(nodes []*Node) ([]Value, error) {
+	values := make([]Value, len(nodes))
+	for i, node := range nodes {
+		if node.Type != Union {
+			value, err := e.evalArgument(node)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+			continue
+		}
+		var cells [][]Value
+		for _, area := range node.Children {
+			value, err := e.evalArgument(area)
+			if err != nil {
+				return nil, err
+			}
+			for _, cell := range flatten(value) {
+				cells = append(cells, []Value{cell})
+			}
+		}
+		values[i] = NewArray(cells)
+	}
+	return values, nil
+}
+
+// evalArgument evaluates a function argument. References are returned as ArrayValue
+// even if they point a single cell so that aggregate functions can distinguish
+// SUM(A1) from SUM("1").
+func (e *evaluator) evalArgument(node *Node) (Value, error) {
+	value, err := e.eval(node)
+	if err != nil {
+		return Value{}, err
+	}
+	if value.Type != ArrayValue && value.Type != ErrorValue && isReferenceNode(node) {
+		value = NewArray([][]Value{{value}})
+	}
+	return value, nil
+}
+
+func isReferenceNode(node *Node) bool {
+	switch node.Type {
+	case SingleToken:
+		return node.Token.Type == Range || node.Token.Type == Name || node.Token.Type == StructuredRef
+	case Function:
+		spec := lookupFunction(node.Token.Text)
+		return spec != nil && spec.reference
+	case Intersection, RangeOp:
+		return true
+	}
+	return false
+}
+
+// liftCall calls a scalar function. If arguments have arrays, it is called for each
+// element and the results are returned as an array.
+func liftCall(values []Value, call func(args *arguments) Value) Value {
+	rows, cols := 1, 1
+	lifted := false
+	for i, value := range values {
+		if value.Type != ArrayValue {
+			continue
+		}
+		r, c := arraySize(value)
+		if r == 1 && c == 1 {
+			values[i] = value.Array[0][0]
+			continue
+		}
+		lifted = true
+		if r > rows {
+			rows = r
+		}
+		if c > cols {
+			cols = c
+		}
+	}
+	if !lifted {
+		return call(&arguments{values: values})
+	}
+	result := make([][]Value, rows)
+	for i := 0; i < rows; i++ {
+		result[i] = make([]Value, cols)
+		for j := 0; j < cols; j++ {
+			element := make([]Value, len(values))
+			missing := false
+			for k, value := range values {
+				v, ok := broadcast(value, i, j)
+				if !ok {
+					missing = true
+					break
+				}
+				element[k] = v
+			}
+			if missing {
+				result[i][j] = NewError(NAError)
+			} else {
+				result[i][j] = call(&arguments{values: element})
+			}
+		}
+	}
+	return NewArray(result)
+}
+
+// arguments is evaluated function arguments. Accessors keep the first
+// coercion error in err so that a function can check it once.
+type arguments struct {
+	values []Value
+	err    ErrorCode
+}
+
+func (a *arguments) len() int {
+	return len(a.values)
+}
+
+// has returns true if the i-th argument is given and not blank.
+func (a *arguments) has(i int) bool {
+	return i < len(a.values) && a.values[i].Type != BlankValue
+}
+
+func (a *arguments) fail(code ErrorCode) {
+	if a.err == "" {
+		a.err = code
+	}
+}
+
+func (a *arguments) value(i int) Value {
+	if i < len(a.values) {
+		return a.values[i]
+	}
+	return Value{}
+}
+
+func (a *arguments) number(i int) float64 {
+	number, code := toNumber(a.value(i))
+	if code != "" {
+		a.fail(code)
+	}
+	return number
+}
+
+func (a *arguments) optNumber(i int, defaultValue float64) float64 {
+	if !a.has(i) {
+		return defaultValue
+	}
+	return a.number(i)
+}
+
+func (a *arguments) text(i int) string {
+	text, code := toText(a.value(i))
+	if code != "" {
+		a.fail(code)
+	}
+	return text
+}
+
+func (a *arguments) optText(i int, defaultValue string) string {
+	if !a.has(i) {
+		return defaultValue
+	}
+	return a.text(i)
+}
+
+func (a *arguments) boolean(i int) bool {
+	b, code := toBool(a.value(i))
+	if code != "" {
+		a.fail(code)
+	}
+	return b
+}
+
+func (a *arguments) optBool(i int, defaultValue bool) bool {
+	if !a.has(i) {
+		return defaultValue
+	}
+	return a.boolean(i)
+}
+
+// array returns the i-th argument as rows. Scalars become 1x1.
+func (a *arguments) array(i int) [][]Value {
+	return toRows(a.value(i))
+}
+
+func (a *arguments) error() Value {
+	return NewError(a.err)
+}
+
+func toRows(v Value) [][]Value {
+	if v.Type == ArrayValue {
+		return v.Array
+	}
+	return [][]Value{{v}}
+}
+
+// flatten returns the elements of v in row major order.
+func flatten(v Value) []Value {
+	if v.Type != ArrayValue {
+		return []Value{v}
+	}
+	var result []Value
+	for _, row := range v.Array {
+		result = append(result, row...)
+	}
+	return result
+}
+
+// collectNumbers collects numbers for aggregate functions like SUM(). Scalar
+// arguments are coerced to numbers. In arrays, only numbers are used unless
+// includeAll is true, where text counts as 0 and booleans as 1 or 0 like AVERAGEA().
+// Errors are returned as ErrorCode.
+func collectNumbers(values []Value, includeAll bool) ([]float64, ErrorCode) {
+	var numbers []float64
+	for _, value := range values {
+		if value.Type != ArrayValue {
+			if value.Type == BlankValue {
+				continue
+			}
+			number, code := toNumber(value)
+			if code != "" {
+				return nil, code
+			}
+			numbers = append(numbers, number)
+			continue
+		}
+		for _, row := range value.Array {
+			for _, cell := range row {
+				switch cell.Type {
+				case NumberValue:
+					numbers = append(numbers, cell.Number)
+				case ErrorValue:
+					return nil, cell.Error
+				case BoolValue:
+					if includeAll {
+						if cell.Bool {
+							numbers = append(numbers, 1)
+						} else {
+							numbers = append(numbers, 0)
+						}
+					}
+				case StringValue:
+					if includeAll {
+						numbers = append(numbers, 0)
+					}
+				}
+			}
+		}
+	}
+	return numbers, ""
+}
+
+// criteria is a condition like ">=10" or "a*" used by SUMIF(), COUNTIFS() and so on.
+type criteria struct {
+	operator string
+	value    Value
+	wildcard bool
+}
+
+func parseCriteria(v Value) criteria {
+	v = scalar(v)
+	if v.Type != StringValue {
+		return criteria{operator: "=", value: v}
+	}
+	text := v.Text
+	operator := "="
+	for _, candidate := range []string{"<=", ">=", "<>", "<", ">", "="} {
+		if strings.HasPrefix(text, candidate) {
+			operator = candidate
+			text = text[len(candidate):]
+			break
+		}
+	}
+	if number, ok := parseNumber(text); ok {
+		return criteria{operator: operator, value: NewNumber(number)}
+	}
+	switch strings.ToUpper(text) {
+	case "TRUE":
+		return criteria{operator: operator, value: NewBool(true)}
+	case "FALSE":
+		return criteria{operator: operator, value: NewBool(false)}
+	}
+	if text == "" {
+		return criteria{operator: operator, value: Value{}}
+	}
+	if errorCode, ok := errorCodes[strings.ToUpper(text)]; ok {
+		return criteria{operator: operator, value: NewError(errorCode)}
+	}
+	return criteria{
+		operator: operator,
+		value:    NewString(text),
+		wildcard: strings.ContainsAny(text, "*?~"),
+	}
+}
+
+func (c criteria) match(v Value) bool {
+	if c.value.Type == BlankValue {
+		empty := v.Type == BlankValue || (v.Type == StringValue && v.Text == "")
+		switch c.operator {
+		case "=":
+			return empty
+		case "<>":
+			return !empty
+		}
+		return false
+	}
+	if v.Type != c.value.Type {
+		// "5" in a cell matches with criteria 5
+		if c.value.Type == NumberValue && v.Type == StringValue && (c.operator == "=" || c.operator == "<>") {
+			if number, ok := parseNumber(v.Text); ok {
+				v = NewNumber(number)
+			}
+		}
+		if v.Type != c.value.Type {
+			return c.operator == "<>"
+		}
+	}
+	if c.wildcard && (c.operator == "=" || c.operator == "<>") {
+		matched := wildcardMatch(c.value.Text, v.Text)
+		return matched == (c.operator == "=")
+	}
+	if v.Type == ErrorValue {
+		return (v.Error == c.value.Error) == (c.operator == "=")
+	}
+	result := compareValues(v, c.value)
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "<>":
+		return result != 0
+	case "<":
+		return result < 0
+	case ">":
+		return result > 0
+	case "<=":
+		return result <= 0
+	case ">=":
+		return result >= 0
+	}
+	return false
+}
+
+// wildcardMatch matches text with a pattern that has * (any characters), ? (a character)
+// and ~ (escape). It is case insensitive.
+func wildcardMatch(pattern, text string) bool {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	return matchRunes(p, t)
+}
+
+func matchRunes(p, t []rune) bool {
+	for len(p) > 0 {
+		switch p[0] {
+		case '*':
+			for len(p) > 0 && p[0] == '*' {
+				p = p[1:]
+			}
+			if len(p) == 0 {
+				return true
+			}
+			for i := 0; i <= len(t); i++ {
+				if matchRunes(p, t[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(t) == 0 {
+				return false
+			}
+		case '~':
+			if len(p) > 1 {
+				p = p[1:]
+			}
+			fallthrough
+		default:
+			if len(t) == 0 || t[0] != p[0] {
+				return false
+			}
+		}
+		p = p[1:]
+		t = t[1:]
+	}
+	return len(t) == 0
+}
@@ -0,0 +1,647 @@
+package xlsxformula
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Excel's 1900 date system: serial 1 is 1900-01-01. Excel treats 1900 as a leap
+// year, so serial 60 is 1900-02-29 which doesn't exist and dates after it are
+// counted from 1899-12-30.
+var dateEpoch time.Time = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// dateSerial returns the serial number of the date. Month and day can overflow like DATE().
+func dateSerial(year, month, day int) (float64, bool) {
+	if year >= 0 && year < 1900 {
+		year += 1900
+	}
+	year += int(math.Floor(float64(month-1) / 12))
+	month = (month-1)%12 + 1
+	if month < 1 {
+		month += 12
+	}
+	if year < 1900 || year > 9999 {
+		return 0, false
+	}
+	if year == 1900 && month == 2 && day == 29 {
+		return 60, true
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	days := math.Round(date.Sub(dateEpoch).Hours() / 24)
+	if days < 61 {
+		days--
+	}
+	if days < 0 || date.Year() > 9999 {
+		return 0, false
+	}
+	return days, true
+}
+
+// serialDate returns year, month and day of the serial number. Serial 0 is 1900-01-00.
+func serialDate(serial float64) (int, int, int, bool) {
+	n := math.Floor(serial)
+	if n < 0 || n > 2958465 {
+		return 0, 0, 0, false
+	}
+	switch {
+	case n == 0:
+		return 1900, 1, 0, true
+	case n == 60:
+		return 1900, 2, 29, true
+	case n < 60:
+		n++
+	}
+	date := dateEpoch.AddDate(0, 0, int(n))
+	return date.Year(), int(date.Month()), date.Day(), true
+}
+
+// serialTime returns hour, minute and second of the fractional part of the serial number.
+func serialTime(serial float64) (int, int, int) {
+	seconds := int(math.Round((serial - math.Floor(serial)) * 86400))
+	if seconds >= 86400 {
+		seconds = 0
+	}
+	return seconds / 3600, seconds / 60 % 60, seconds % 60
+}
+
+// timeSerial converts time.Time to a serial number.
+func timeSerial(t time.Time) float64 {
+	date, _ := dateSerial(t.Year(), int(t.Month()), t.Day())
+	return date + float64(t.Hour()*3600+t.Minute()*60+t.Second())/86400
+}
+
+// weekday returns the day of week of the serial number (0 is Sunday). It is consistent
+// with Excel's calendar including the 1900-02-29.
+func weekday(serial float64) int {
+	return int(math.Mod(math.Floor(serial)+6, 7))
+}
+
+var dateLayouts []string = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006/1/2",
+	"1/2/2006",
+	"1/2/06",
+	"2-Jan-2006",
+	"2-Jan-06",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 January 2006",
+}
+
+var timeLayouts []string = []string{
+	"15:04",
+	"15:04:05",
+	"3:04 PM",
+	"3:04:05 PM",
+	"3 PM",
+}
+
+// parseDateTime parses text like "2015-10-21", "10/21/2015 16:29" or "4:29 PM" to a serial number.
+func parseDateTime(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, strings.ToUpper(text)); err == nil {
+			return float64(t.Hour()*3600+t.Minute()*60+t.Second()) / 86400, true
+		}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return dateSerial(t.Year(), int(t.Month()), t.Day())
+		}
+		for _, timeLayout := range timeLayouts {
+			if t, err := time.Parse(layout+" "+timeLayout, text); err == nil {
+				return timeSerial(t), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// serial returns the i-th argument as a date serial number. Text is parsed as a date.
+func (a *arguments) serial(i int) float64 {
+	value := scalar(a.value(i))
+	if value.Type == StringValue {
+		if _, ok := parseNumber(value.Text); !ok {
+			if serial, ok := parseDateTime(value.Text); ok {
+				return serial
+			}
+		}
+	}
+	serial := a.number(i)
+	if serial < 0 {
+		a.fail(NumError)
+	}
+	return serial
+}
+
+func init() {
+	registerScalar("DATE", 3, 3, func(args *arguments) Value {
+		year, month, day := math.Trunc(args.number(0)), math.Trunc(args.number(1)), math.Trunc(args.number(2))
+		if args.err != "" {
+			return args.error()
+		}
+		serial, ok := dateSerial(int(year), int(month), int(day))
+		if !ok {
+			return NewError(NumError)
+		}
+		return NewNumber(serial)
+	})
+	registerScalar("TIME", 3, 3, func(args *arguments) Value {
+		hour, minute, second := math.Trunc(args.number(0)), math.Trunc(args.number(1)), math.Trunc(args.number(2))
+		if args.err != "" {
+			return args.error()
+		}
+		seconds := hour*3600 + minute*60 + second
+		if seconds < 0 {
+			return NewError(NumError)
+		}
+		return NewNumber(math.Mod(seconds, 86400) / 86400)
+	})
+	registerScalar("DATEVALUE", 1, 1, func(args *arguments) Value {
+		text := args.text(0)
+		if args.err != "" {
+			return args.error()
+		}
+		serial, ok := parseDateTime(text)
+		if !ok {
+			return NewError(ValueError)
+		}
+		return NewNumber(math.Floor(serial))
+	})
+	registerScalar("TIMEVALUE", 1, 1, func(args *arguments) Value {
+		text := args.text(0)
+		if args.err != "" {
+			return args.error()
+		}
+		serial, ok := parseDateTime(text)
+		if !ok {
+			return NewError(ValueError)
+		}
+		return NewNumber(serial - math.Floor(serial))
+	})
+	datePart := func(name string, part func(year, month, day int) int) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			serial := args.serial(0)
+			if args.err != "" {
+				return args.error()
+			}
+			year, month, day, ok := serialDate(serial)
+			if !ok {
+				return NewError(NumError)
+			}
+			return NewNumber(float64(part(year, month, day)))
+		})
+	}
+	datePart("YEAR", func(year, month, day int) int { return year })
+	datePart("MONTH", func(year, month, day int) int { return month })
+	datePart("DAY", func(year, month, day int) int { return day })
+	timePart := func(name string, part func(hour, minute, second int) int) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			serial := args.serial(0)
+			if args.err != "" {
+				return args.error()
+			}
+			return NewNumber(float64(part(serialTime(serial))))
+		})
+	}
+	timePart("HOUR", func(hour, minute, second int) int { return hour })
+	timePart("MINUTE", func(hour, minute, second int) int { return minute })
+	timePart("SECOND", func(hour, minute, second int) int { return second })
+	registerFunction("TODAY", &functionSpec{
+		volatile: true,
+		call: func(args *arguments) Value {
+			return NewNumber(math.Floor(timeSerial(time.Now())))
+		},
+	})
+	registerFunction("NOW", &functionSpec{
+		volatile: true,
+		call: func(args *arguments) Value {
+			return NewNumber(timeSerial(time.Now()))
+		},
+	})
+	registerScalar("WEEKDAY", 1, 2, func(args *arguments) Value {
+		serial, returnType := args.serial(0), args.optNumber(1, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		day := weekday(serial)
+		switch returnType {
+		case 1, 17:
+			return NewNumber(float64(day + 1))
+		case 2, 11:
+			return NewNumber(float64((day+6)%7 + 1))
+		case 3:
+			return NewNumber(float64((day + 6) % 7))
+		case 12, 13, 14, 15, 16:
+			start := int(returnType) - 10 // 2 is Tuesday
+			return NewNumber(float64((day-start+7)%7 + 1))
+		}
+		return NewError(NumError)
+	})
+	registerScalar("WEEKNUM", 1, 2, func(args *arguments) Value {
+		serial, returnType := args.serial(0), args.optNumber(1, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		year, _, _, ok := serialDate(serial)
+		if !ok {
+			return NewError(NumError)
+		}
+		if returnType == 21 {
+			_, week := dateEpoch.AddDate(0, 0, int(math.Floor(serial))).ISOWeek()
+			return NewNumber(float64(week))
+		}
+		var start int // first day of week, 0 is Sunday
+		switch {
+		case returnType == 1:
+			start = 0
+		case returnType == 2:
+			start = 1
+		case returnType >= 11 && returnType <= 17:
+			start = (int(returnType) - 10) % 7
+		default:
+			return NewError(NumError)
+		}
+		newYear, _ := dateSerial(year, 1, 1)
+		offset := (weekday(newYear) - start + 7) % 7
+		return NewNumber(math.Floor((math.Floor(serial)-newYear+float64(offset))/7) + 1)
+	})
+	registerScalar("ISOWEEKNUM", 1, 1, func(args *arguments) Value {
+		serial := args.serial(0)
+		if args.err != "" {
+			return args.error()
+		}
+		year, month, day, ok := serialDate(serial)
+		if !ok {
+			return NewError(NumError)
+		}
+		_, week := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).ISOWeek()
+		return NewNumber(float64(week))
+	})
+	registerScalar("EDATE", 2, 2, func(args *arguments) Value {
+		serial, months := args.serial(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		return addMonths(serial, int(months), false)
+	})
+	registerScalar("EOMONTH", 2, 2, func(args *arguments) Value {
+		serial, months := args.serial(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		return addMonths(serial, int(months), true)
+	})
+	registerScalar("DAYS", 2, 2, func(args *arguments) Value {
+		end, start := args.serial(0), args.serial(1)
+		if args.err != "" {
+			return args.error()
+		}
+		return NewNumber(math.Floor(end) - math.Floor(start))
+	})
+	registerScalar("DATEDIF", 3, 3, func(args *arguments) Value {
+		start, end, unit := args.serial(0), args.serial(1), strings.ToUpper(args.text(2))
+		if args.err != "" {
+			return args.error()
+		}
+		if start > end {
+			return NewError(NumError)
+		}
+		y1, m1, d1, ok1 := serialDate(start)
+		y2, m2, d2, ok2 := serialDate(end)
+		if !ok1 || !ok2 {
+			return NewError(NumError)
+		}
+		months := (y2-y1)*12 + m2 - m1
+		if d2 < d1 {
+			months--
+		}
+		switch unit {
+		case "Y":
+			return NewNumber(float64(months / 12))
+		case "M":
+			return NewNumber(float64(months))
+		case "D":
+			return NewNumber(math.Floor(end) - math.Floor(start))
+		case "YM":
+			return NewNumber(float64(months % 12))
+		case "MD":
+			if d2 >= d1 {
+				return NewNumber(float64(d2 - d1))
+			}
+			previous, _ := dateSerial(y2, m2, 0)
+			_, _, lastDay, _ := serialDate(previous)
+			days := lastDay - d1 + d2
+			if d1 > lastDay {
+				days = d2
+			}
+			return NewNumber(float64(days))
+		case "YD":
+			anniversary, ok := dateSerial(y2, m1, d1)
+			if !ok {
+				return NewError(NumError)
+			}
+			if anniversary > math.Floor(end) {
+				anniversary, _ = dateSerial(y2-1, m1, d1)
+			}
+			return NewNumber(math.Floor(end) - anniversary)
+		}
+		return NewError(NumError)
+	})
+	registerScalar("DAYS360", 2, 3, func(args *arguments) Value {
+		start, end, european := args.serial(0), args.serial(1), args.optBool(2, false)
+		if args.err != "" {
+			return args.error()
+		}
+		y1, m1, d1, ok1 := serialDate(start)
+		y2, m2, d2, ok2 := serialDate(end)
+		if !ok1 || !ok2 {
+			return NewError(NumError)
+		}
+		return NewNumber(days360(y1, m1, d1, y2, m2, d2, european))
+	})
+	registerArray("NETWORKDAYS", 2, 3, func(args *arguments) Value {
+		start, end := math.Floor(args.serial(0)), math.Floor(args.serial(1))
+		if args.err != "" {
+			return args.error()
+		}
+		holidays, code := holidaySet(args, 2)
+		if code != "" {
+			return NewError(code)
+		}
+		sign := 1.0
+		if start > end {
+			start, end, sign = end, start, -1
+		}
+		count := 0.0
+		for day := start; day <= end; day++ {
+			if isWorkday(day, holidays) {
+				count++
+			}
+		}
+		return NewNumber(sign * count)
+	})
+	registerArray("WORKDAY", 2, 3, func(args *arguments) Value {
+		start, days := math.Floor(args.serial(0)), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		holidays, code := holidaySet(args, 2)
+		if code != "" {
+			return NewError(code)
+		}
+		step := 1.0
+		if days < 0 {
+			step = -1
+		}
+		day := start
+		for remaining := math.Abs(days); remaining > 0; {
+			day += step
+			if isWorkday(day, holidays) {
+				remaining--
+			}
+		}
+		if day < 0 {
+			return NewError(NumError)
+		}
+		return NewNumber(day)
+	})
+	registerScalar("YEARFRAC", 2, 3, func(args *arguments) Value {
+		start, end, basis := math.Floor(args.serial(0)), math.Floor(args.serial(1)), math.Trunc(args.optNumber(2, 0))
+		if args.err != "" {
+			return args.error()
+		}
+		if start > end {
+			start, end = end, start
+		}
+		y1, m1, d1, ok1 := serialDate(start)
+		y2, m2, d2, ok2 := serialDate(end)
+		if !ok1 || !ok2 {
+			return NewError(NumError)
+		}
+		switch basis {
+		case 0:
+			return NewNumber(days360(y1, m1, d1, y2, m2, d2, false) / 360)
+		case 1:
+			return NewNumber(actualYearFraction(start, end, y1, m1, d1, y2, m2, d2))
+		case 2:
+			return NewNumber((end - start) / 360)
+		case 3:
+			return NewNumber((end - start) / 365)
+		case 4:
+			return NewNumber(days360(y1, m1, d1, y2, m2, d2, true) / 360)
+		}
+		return NewError(NumError)
+	})
+}
+
+func addMonths(serial float64, months int, endOfMonth bool) Value {
+	year, month, day, ok := serialDate(serial)
+	if !ok {
+		return NewError(NumError)
+	}
+	var result float64
+	if endOfMonth {
+		result, ok = dateSerial(year, month+months+1, 0)
+	} else {
+		last, _ := dateSerial(year, month+months+1, 0)
+		_, _, lastDay, _ := serialDate(last)
+		if day > lastDay {
+			day = lastDay
+		}
+		result, ok = dateSerial(year, month+months, day)
+	}
+	if !ok {
+		return NewError(NumError)
+	}
+	return NewNumber(result)
+}
+
+func days360(y1, m1, d1, y2, m2, d2 int, european bool) float64 {
+	if european {
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 {
+			d2 = 30
+		}
+	} else {
+		lastOfFebruary := func(y, m, d int) bool {
+			next, _ := dateSerial(y, m, d+1)
+			_, nextMonth, _, _ := serialDate(next)
+			return m == 2 && nextMonth == 3
+		}
+		if lastOfFebruary(y1, m1, d1) {
+			if lastOfFebruary(y2, m2, d2) {
+				d2 = 30
+			}
+			d1 = 30
+		}
+		if d2 == 31 && d1 >= 30 {
+			d2 = 30
+		}
+		if d1 == 31 {
+			d1 = 30
+		}
+	}
+	return float64((y2-y1)*360 + (m2-m1)*30 + d2 - d1)
+}
+
+// actualYearFraction implements the actual/actual basis of YEARFRAC().
+func actualYearFraction(start, end float64, y1, m1, d1, y2, m2, d2 int) float64 {
+	isLeap := func(y int) bool {
+		return y%4 == 0 && (y%100 != 0 || y%400 == 0)
+	}
+	containsLeapDay := func(y int) bool {
+		if !isLeap(y) {
+			return false
+		}
+		leapDay, _ := dateSerial(y, 2, 29)
+		return start <= leapDay && leapDay <= end
+	}
+	withinYear := y1 == y2 || (y2 == y1+1 && (m2 < m1 || (m2 == m1 && d2 <= d1)))
+	if withinYear {
+		length := 365.0
+		if (y1 == y2 && isLeap(y1)) || containsLeapDay(y1) || containsLeapDay(y2) {
+			length = 366
+		}
+		return (end - start) / length
+	}
+	first, _ := dateSerial(y1, 1, 1)
+	last, _ := dateSerial(y2+1, 1, 1)
+	average := (last - first) / float64(y2-y1+1)
+	return (end - start) / average
+}
+
+func holidaySet(args *arguments, i int) (map[float64]bool, ErrorCode) {
+	holidays := map[float64]bool{}
+	if !args.has(i) {
+		return holidays, ""
+	}
+	for _, cell := range flatten(args.value(i)) {
+		switch cell.Type {
+		case ErrorValue:
+			return nil, cell.Error
+		case NumberValue:
+			holidays[math.Floor(cell.Number)] = true
+		case StringValue:
+			serial, ok := parseDateTime(cell.Text)
+			if !ok {
+				return nil, ValueError
+			}
+			holidays[math.Floor(serial)] = true
+		}
+	}
+	return holidays, ""
+}
+
+func isWorkday(serial float64, holidays map[float64]bool) bool {
+	day := weekday(serial)
+	return day != 0 && day != 6 && !holidays[serial]
+}
+
+// formatDateTime formats the serial number with date/time codes of number formats.
+func formatDateTime(serial float64, format string) (string, bool) {
+	year, month, day, ok := serialDate(serial)
+	if !ok {
+		return "", false
+	}
+	hour, minute, second := serialTime(serial)
+	ampm := strings.Contains(strings.ToUpper(format), "AM/PM")
+	var builder strings.Builder
+	lastWasHour := false
+	for i := 0; i < len(format); {
+		ch := format[i]
+		if ch == '"' {
+			end := strings.IndexByte(format[i+1:], '"')
+			if end == -1 {
+				end = len(format) - i - 1
+			}
+			builder.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if ch == '\\' && i+1 < len(format) {
+			builder.WriteByte(format[i+1])
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(format[i:]), "AM/PM") {
+			if hour < 12 {
+				builder.WriteString("AM")
+			} else {
+				builder.WriteString("PM")
+			}
+			i += 5
+			continue
+		}
+		lower := ch | 0x20
+		count := 1
+		for i+count < len(format) && format[i+count]|0x20 == lower {
+			count++
+		}
+		switch lower {
+		case 'y':
+			if count <= 2 {
+				builder.WriteString(padNumber(year%100, 2))
+			} else {
+				builder.WriteString(padNumber(year, 4))
+			}
+		case 'm':
+			// "m" after hours or before seconds means minutes
+			rest := strings.TrimLeft(strings.ToLower(format[i+count:]), ":")
+			if count <= 2 && (lastWasHour || strings.HasPrefix(rest, "s")) {
+				builder.WriteString(padNumber(minute, count))
+			} else {
+				switch count {
+				case 1, 2:
+					builder.WriteString(padNumber(month, count))
+				case 3:
+					builder.WriteString(time.Month(month).String()[:3])
+				case 5:
+					builder.WriteString(time.Month(month).String()[:1])
+				default:
+					builder.WriteString(time.Month(month).String())
+				}
+			}
+		case 'd':
+			switch count {
+			case 1, 2:
+				builder.WriteString(padNumber(day, count))
+			case 3:
+				builder.WriteString(time.Weekday(weekday(serial)).String()[:3])
+			default:
+				builder.WriteString(time.Weekday(weekday(serial)).String())
+			}
+		case 'h':
+			h := hour
+			if ampm {
+				h = hour % 12
+				if h == 0 {
+					h = 12
+				}
+			}
+			builder.WriteString(padNumber(h, count))
+		case 's':
+			builder.WriteString(padNumber(second, count))
+		default:
+			builder.WriteString(format[i : i+count])
+		}
+		if lower != ':' && lower != ' ' {
+			lastWasHour = lower == 'h'
+		}
+		i += count
+	}
+	return builder.String(), true
+}
+
+func padNumber(n, width int) string {
+	text := strings.TrimLeft(formatNumber(float64(n)), "-")
+	for len(text) < width {
+		text = "0" + text
+	}
+	return text
+}
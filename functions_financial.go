@@ -0,0 +1,275 @@
+package xlsxformula
+
+import (
+	"math"
+)
+
+func init() {
+	registerScalar("PMT", 3, 5, func(args *arguments) Value {
+		rate, nper, pv, fv, due := args.number(0), args.number(1), args.number(2), args.optNumber(3, 0), args.optNumber(4, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if nper == 0 {
+			return NewError(NumError)
+		}
+		return numberResult(payment(rate, nper, pv, fv, due))
+	})
+	registerScalar("IPMT", 4, 6, func(args *arguments) Value {
+		rate, per, nper, pv, fv, due := args.number(0), args.number(1), args.number(2), args.number(3), args.optNumber(4, 0), args.optNumber(5, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if per < 1 || per > nper {
+			return NewError(NumError)
+		}
+		return numberResult(interestPayment(rate, per, nper, pv, fv, due))
+	})
+	registerScalar("PPMT", 4, 6, func(args *arguments) Value {
+		rate, per, nper, pv, fv, due := args.number(0), args.number(1), args.number(2), args.number(3), args.optNumber(4, 0), args.optNumber(5, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if per < 1 || per > nper {
+			return NewError(NumError)
+		}
+		return numberResult(payment(rate, nper, pv, fv, due) - interestPayment(rate, per, nper, pv, fv, due))
+	})
+	registerScalar("PV", 3, 5, func(args *arguments) Value {
+		rate, nper, pmt, fv, due := args.number(0), args.number(1), args.number(2), args.optNumber(3, 0), args.optNumber(4, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if rate == 0 {
+			return numberResult(-fv - pmt*nper)
+		}
+		growth := math.Pow(1+rate, nper)
+		return numberResult(-(fv + pmt*(1+rate*due)*(growth-1)/rate) / growth)
+	})
+	registerScalar("FV", 3, 5, func(args *arguments) Value {
+		rate, nper, pmt, pv, due := args.number(0), args.number(1), args.number(2), args.optNumber(3, 0), args.optNumber(4, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(futureValue(rate, nper, pmt, pv, due))
+	})
+	registerScalar("NPER", 3, 5, func(args *arguments) Value {
+		rate, pmt, pv, fv, due := args.number(0), args.number(1), args.number(2), args.optNumber(3, 0), args.optNumber(4, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if rate == 0 {
+			if pmt == 0 {
+				return NewError(NumError)
+			}
+			return numberResult(-(pv + fv) / pmt)
+		}
+		adjusted := pmt * (1 + rate*due)
+		ratio := (adjusted - fv*rate) / (adjusted + pv*rate)
+		if ratio <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult(math.Log(ratio) / math.Log(1+rate))
+	})
+	registerScalar("RATE", 3, 6, func(args *arguments) Value {
+		nper, pmt, pv, fv, due, guess := args.number(0), args.number(1), args.number(2), args.optNumber(3, 0), args.optNumber(4, 0), args.optNumber(5, 0.1)
+		if args.err != "" {
+			return args.error()
+		}
+		f := func(rate float64) float64 {
+			if rate == 0 {
+				return pv + pmt*nper + fv
+			}
+			growth := math.Pow(1+rate, nper)
+			return pv*growth + pmt*(1+rate*due)*(growth-1)/rate + fv
+		}
+		rate, ok := newton(f, guess)
+		if !ok {
+			return NewError(NumError)
+		}
+		return NewNumber(rate)
+	})
+	registerArray("NPV", 2, -1, func(args *arguments) Value {
+		rate := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		numbers, code := collectNumbers(args.values[1:], false)
+		if code != "" {
+			return NewError(code)
+		}
+		if rate == -1 {
+			return NewError(Div0Error)
+		}
+		return numberResult(netPresentValue(rate, numbers))
+	})
+	registerArray("IRR", 1, 2, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values[:1], false)
+		if code != "" {
+			return NewError(code)
+		}
+		guess := args.optNumber(1, 0.1)
+		if args.err != "" {
+			return args.error()
+		}
+		rate, ok := newton(func(rate float64) float64 {
+			return netPresentValue(rate, numbers) * (1 + rate)
+		}, guess)
+		if !ok {
+			return NewError(NumError)
+		}
+		return NewNumber(rate)
+	})
+	registerScalar("SLN", 3, 3, func(args *arguments) Value {
+		cost, salvage, life := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if life == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult((cost - salvage) / life)
+	})
+	registerScalar("SYD", 4, 4, func(args *arguments) Value {
+		cost, salvage, life, period := args.number(0), args.number(1), args.number(2), args.number(3)
+		if args.err != "" {
+			return args.error()
+		}
+		if life <= 0 || period <= 0 || period > life {
+			return NewError(NumError)
+		}
+		return numberResult((cost - salvage) * (life - period + 1) * 2 / (life * (life + 1)))
+	})
+	registerScalar("DDB", 4, 5, func(args *arguments) Value {
+		cost, salvage, life, period, factor := args.number(0), args.number(1), args.number(2), args.number(3), args.optNumber(4, 2)
+		if args.err != "" {
+			return args.error()
+		}
+		if cost < 0 || salvage < 0 || life <= 0 || period <= 0 || period > life || factor <= 0 {
+			return NewError(NumError)
+		}
+		value := cost
+		var depreciation float64
+		for i := 1.0; i <= period; i++ {
+			depreciation = math.Min(value*factor/life, math.Max(value-salvage, 0))
+			value -= depreciation
+		}
+		return NewNumber(depreciation)
+	})
+	registerScalar("EFFECT", 2, 2, func(args *arguments) Value {
+		rate, periods := args.number(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if rate <= 0 || periods < 1 {
+			return NewError(NumError)
+		}
+		return numberResult(math.Pow(1+rate/periods, periods) - 1)
+	})
+	registerScalar("NOMINAL", 2, 2, func(args *arguments) Value {
+		rate, periods := args.number(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if rate <= 0 || periods < 1 {
+			return NewError(NumError)
+		}
+		return numberResult((math.Pow(1+rate, 1/periods) - 1) * periods)
+	})
+	registerScalar("PDURATION", 3, 3, func(args *arguments) Value {
+		rate, pv, fv := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if rate <= 0 || pv <= 0 || fv <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult((math.Log(fv) - math.Log(pv)) / math.Log(1+rate))
+	})
+	registerScalar("RRI", 3, 3, func(args *arguments) Value {
+		nper, pv, fv := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if nper <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult(math.Pow(fv/pv, 1/nper) - 1)
+	})
+	registerArray("FVSCHEDULE", 2, 2, func(args *arguments) Value {
+		principal := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		rates, code := collectNumbers(args.values[1:], false)
+		if code != "" {
+			return NewError(code)
+		}
+		for _, rate := range rates {
+			principal *= 1 + rate
+		}
+		return numberResult(principal)
+	})
+}
+
+func payment(rate, nper, pv, fv, due float64) float64 {
+	if rate == 0 {
+		return -(pv + fv) / nper
+	}
+	growth := math.Pow(1+rate, nper)
+	return -rate * (fv + pv*growth) / ((1 + rate*due) * (growth - 1))
+}
+
+func futureValue(rate, nper, pmt, pv, due float64) float64 {
+	if rate == 0 {
+		return -pv - pmt*nper
+	}
+	growth := math.Pow(1+rate, nper)
+	return -(pv*growth + pmt*(1+rate*due)*(growth-1)/rate)
+}
+
+func interestPayment(rate, per, nper, pv, fv, due float64) float64 {
+	pmt := payment(rate, nper, pv, fv, due)
+	var interest float64
+	switch {
+	case per == 1 && due != 0:
+		return 0
+	case per == 1:
+		interest = -pv
+	case due != 0:
+		interest = futureValue(rate, per-2, pmt, pv, 1) - pmt
+	default:
+		interest = futureValue(rate, per-1, pmt, pv, 0)
+	}
+	return interest * rate
+}
+
+func netPresentValue(rate float64, numbers []float64) float64 {
+	result := 0.0
+	for i, number := range numbers {
+		result += number / math.Pow(1+rate, float64(i+1))
+	}
+	return result
+}
+
+// newton finds a root of f by Newton's method with numerical derivative.
+func newton(f func(x float64) float64, guess float64) (float64, bool) {
+	x := guess
+	for i := 0; i < 100; i++ {
+		y := f(x)
+		if math.Abs(y) < 1e-10 {
+			return x, true
+		}
+		h := 1e-7 * math.Max(1, math.Abs(x))
+		derivative := (f(x+h) - y) / h
+		if derivative == 0 || math.IsNaN(derivative) {
+			return 0, false
+		}
+		next := x - y/derivative
+		if math.Abs(next-x) < 1e-12 {
+			return next, true
+		}
+		x = next
+	}
+	return 0, false
+}
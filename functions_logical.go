@@ -0,0 +1,285 @@
+package xlsxformula
+
+import (
+	"math"
+)
+
+func init() {
+	// logical
+	registerLazy("IF", 1, 3, func(e *evaluator, nodes []*Node) (Value, error) {
+		condition, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		if condition.Type == ArrayValue {
+			// IF({TRUE,FALSE}, ...) chooses element-wise
+			whenTrue, err := e.evalOptional(nodes, 1, NewBool(true))
+			if err != nil {
+				return Value{}, err
+			}
+			whenFalse, err := e.evalOptional(nodes, 2, NewBool(false))
+			if err != nil {
+				return Value{}, err
+			}
+			return liftCall([]Value{condition, whenTrue, whenFalse}, func(args *arguments) Value {
+				b := args.boolean(0)
+				if args.err != "" {
+					return args.error()
+				}
+				if b {
+					return args.value(1)
+				}
+				return args.value(2)
+			}), nil
+		}
+		b, code := toBool(condition)
+		if code != "" {
+			return NewError(code), nil
+		}
+		if b {
+			return e.evalOptional(nodes, 1, NewBool(true))
+		}
+		return e.evalOptional(nodes, 2, NewBool(false))
+	})
+	registerLazy("IFS", 2, -1, func(e *evaluator, nodes []*Node) (Value, error) {
+		if len(nodes)%2 != 0 {
+			return NewError(NAError), nil
+		}
+		for i := 0; i < len(nodes); i += 2 {
+			condition, err := e.eval(nodes[i])
+			if err != nil {
+				return Value{}, err
+			}
+			b, code := toBool(condition)
+			if code != "" {
+				return NewError(code), nil
+			}
+			if b {
+				return e.eval(nodes[i+1])
+			}
+		}
+		return NewError(NAError), nil
+	})
+	registerLazy("IFERROR", 2, 2, func(e *evaluator, nodes []*Node) (Value, error) {
+		value, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		if value.Type == ErrorValue {
+			return e.eval(nodes[1])
+		}
+		if value.Type == ArrayValue {
+			fallback, err := e.eval(nodes[1])
+			if err != nil {
+				return Value{}, err
+			}
+			return lift2(value, fallback, func(a, b Value) Value {
+				if a.Type == ErrorValue {
+					return b
+				}
+				return a
+			}), nil
+		}
+		return value, nil
+	})
+	registerLazy("IFNA", 2, 2, func(e *evaluator, nodes []*Node) (Value, error) {
+		value, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		if value.Type == ErrorValue && value.Error == NAError {
+			return e.eval(nodes[1])
+		}
+		return value, nil
+	})
+	registerLazy("SWITCH", 3, -1, func(e *evaluator, nodes []*Node) (Value, error) {
+		value, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		value = scalar(value)
+		if value.Type == ErrorValue {
+			return value, nil
+		}
+		i := 1
+		for ; i+1 < len(nodes); i += 2 {
+			candidate, err := e.eval(nodes[i])
+			if err != nil {
+				return Value{}, err
+			}
+			candidate = scalar(candidate)
+			if candidate.Type == ErrorValue {
+				return candidate, nil
+			}
+			if candidate.Type == value.Type && compareValues(candidate, value) == 0 {
+				return e.eval(nodes[i+1])
+			}
+		}
+		if i < len(nodes) {
+			return e.eval(nodes[i])
+		}
+		return NewError(NAError), nil
+	})
+	registerLazy("CHOOSE", 2, -1, func(e *evaluator, nodes []*Node) (Value, error) {
+		index, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		number, code := toNumber(index)
+		if code != "" {
+			return NewError(code), nil
+		}
+		number = math.Trunc(number)
+		if number < 1 || int(number) >= len(nodes) {
+			return NewError(ValueError), nil
+		}
+		return e.eval(nodes[int(number)])
+	})
+	logical := func(name string, f func(trues, total int) bool) {
+		registerArray(name, 1, -1, func(args *arguments) Value {
+			trues, total := 0, 0
+			for _, value := range args.values {
+				if value.Type != ArrayValue {
+					if value.Type == BlankValue {
+						continue
+					}
+					b, code := toBool(value)
+					if code != "" {
+						return NewError(code)
+					}
+					total++
+					if b {
+						trues++
+					}
+					continue
+				}
+				for _, cell := range flatten(value) {
+					switch cell.Type {
+					case ErrorValue:
+						return cell
+					case NumberValue, BoolValue:
+						total++
+						if b, _ := toBool(cell); b {
+							trues++
+						}
+					}
+				}
+			}
+			if total == 0 {
+				return NewError(ValueError)
+			}
+			return NewBool(f(trues, total))
+		})
+	}
+	logical("AND", func(trues, total int) bool { return trues == total })
+	logical("OR", func(trues, total int) bool { return trues > 0 })
+	logical("XOR", func(trues, total int) bool { return trues%2 == 1 })
+	registerScalar("NOT", 1, 1, func(args *arguments) Value {
+		b := args.boolean(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return NewBool(!b)
+	})
+	registerScalar("TRUE", 0, 0, func(args *arguments) Value {
+		return NewBool(true)
+	})
+	registerScalar("FALSE", 0, 0, func(args *arguments) Value {
+		return NewBool(false)
+	})
+
+	// information
+	is := func(name string, f func(v Value) bool) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			return NewBool(f(args.value(0)))
+		})
+	}
+	is("ISBLANK", func(v Value) bool { return v.Type == BlankValue })
+	is("ISERR", func(v Value) bool { return v.Type == ErrorValue && v.Error != NAError })
+	is("ISERROR", func(v Value) bool { return v.Type == ErrorValue })
+	is("ISNA", func(v Value) bool { return v.Type == ErrorValue && v.Error == NAError })
+	is("ISLOGICAL", func(v Value) bool { return v.Type == BoolValue })
+	is("ISNUMBER", func(v Value) bool { return v.Type == NumberValue })
+	is("ISTEXT", func(v Value) bool { return v.Type == StringValue })
+	is("ISNONTEXT", func(v Value) bool { return v.Type != StringValue })
+	parity := func(name string, odd bool) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			if args.value(0).Type == BoolValue {
+				return NewError(ValueError)
+			}
+			number := math.Trunc(args.number(0))
+			if args.err != "" {
+				return args.error()
+			}
+			return NewBool((math.Mod(math.Abs(number), 2) == 1) == odd)
+		})
+	}
+	parity("ISEVEN", false)
+	parity("ISODD", true)
+	registerLazy("ISREF", 1, 1, func(e *evaluator, nodes []*Node) (Value, error) {
+		return NewBool(isReferenceNode(nodes[0])), nil
+	})
+	registerScalar("N", 1, 1, func(args *arguments) Value {
+		value := args.value(0)
+		switch value.Type {
+		case NumberValue, ErrorValue:
+			return value
+		case BoolValue:
+			if value.Bool {
+				return NewNumber(1)
+			}
+		}
+		return NewNumber(0)
+	})
+	registerScalar("NA", 0, 0, func(args *arguments) Value {
+		return NewError(NAError)
+	})
+	registerArray("TYPE", 1, 1, func(args *arguments) Value {
+		value := args.value(0)
+		if rows, cols := arraySize(value); value.Type == ArrayValue && rows == 1 && cols == 1 {
+			value = value.Array[0][0]
+		}
+		switch value.Type {
+		case BlankValue, NumberValue:
+			return NewNumber(1)
+		case StringValue:
+			return NewNumber(2)
+		case BoolValue:
+			return NewNumber(4)
+		case ErrorValue:
+			return NewNumber(16)
+		}
+		return NewNumber(64)
+	})
+	registerScalar("ERROR.TYPE", 1, 1, func(args *arguments) Value {
+		value := args.value(0)
+		if value.Type != ErrorValue {
+			return NewError(NAError)
+		}
+		codes := map[ErrorCode]float64{
+			NullError:        1,
+			Div0Error:        2,
+			ValueError:       3,
+			RefError:         4,
+			NameError:        5,
+			NumError:         6,
+			NAError:          7,
+			GettingDataError: 8,
+			SpillError:       9,
+			CalcError:        14,
+		}
+		return NewNumber(codes[value.Error])
+	})
+}
+
+// evalOptional evaluates the i-th argument. If it is not given, defaultValue is returned.
+// An empty argument like IF(A1,,1) is evaluated to 0.
+func (e *evaluator) evalOptional(nodes []*Node, i int, defaultValue Value) (Value, error) {
+	if i >= len(nodes) {
+		return defaultValue, nil
+	}
+	if nodes[i].Type == Expression && len(nodes[i].Children) == 0 {
+		return NewNumber(0), nil
+	}
+	return e.eval(nodes[i])
+}
@@ -0,0 +1,803 @@
+package xlsxformula
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	registerLazy("ROW", 0, 1, func(e *evaluator, nodes []*Node) (Value, error) {
+		return e.position(nodes, true)
+	})
+	registerLazy("COLUMN", 0, 1, func(e *evaluator, nodes []*Node) (Value, error) {
+		return e.position(nodes, false)
+	})
+	registerLazy("AREAS", 1, 1, func(e *evaluator, nodes []*Node) (Value, error) {
+		if nodes[0].Type == Union {
+			return NewNumber(float64(len(nodes[0].Children))), nil
+		}
+		if !isReferenceNode(nodes[0]) {
+			return NewError(ValueError), nil
+		}
+		return NewNumber(1), nil
+	})
+	registerArray("ROWS", 1, 1, func(args *arguments) Value {
+		rows, _ := arraySize(args.value(0))
+		return NewNumber(float64(rows))
+	})
+	registerArray("COLUMNS", 1, 1, func(args *arguments) Value {
+		_, cols := arraySize(args.value(0))
+		return NewNumber(float64(cols))
+	})
+	registerFunction("OFFSET", &functionSpec{
+		minArgs:   3,
+		maxArgs:   5,
+		volatile:  true,
+		reference: true,
+		lazy: func(e *evaluator, nodes []*Node) (Value, error) {
+			return e.resolveReference(e.offsetReference(nodes))
+		},
+	})
+	registerFunction("INDIRECT", &functionSpec{
+		minArgs:   1,
+		maxArgs:   2,
+		volatile:  true,
+		reference: true,
+		lazy: func(e *evaluator, nodes []*Node) (Value, error) {
+			return e.resolveReference(e.indirectReference(nodes))
+		},
+	})
+	index := func(args *arguments) Value {
+		rows := args.array(0)
+		if args.value(0).Type == ErrorValue {
+			return args.value(0)
+		}
+		row, col, area := math.Trunc(args.number(1)), math.Trunc(args.optNumber(2, 0)), math.Trunc(args.optNumber(3, 1))
+		if args.err != "" {
+			return args.error()
+		}
+		if area != 1 {
+			return NewError(RefError)
+		}
+		height, width := len(rows), 0
+		if height > 0 {
+			width = len(rows[0])
+		}
+		if !args.has(2) && height == 1 {
+			// INDEX({1,2,3}, 2) picks from a single row
+			row, col = 1, row
+		}
+		if row < 0 || col < 0 || int(row) > height || int(col) > width {
+			return NewError(RefError)
+		}
+		switch {
+		case row == 0 && col == 0:
+			return NewArray(rows)
+		case row == 0:
+			column := make([][]Value, height)
+			for i := range rows {
+				column[i] = []Value{rows[i][int(col)-1]}
+			}
+			return NewArray(column)
+		case col == 0:
+			if width == 1 {
+				return rows[int(row)-1][0]
+			}
+			return NewArray([][]Value{rows[int(row)-1]})
+		}
+		return rows[int(row)-1][int(col)-1]
+	}
+	registerFunction("INDEX", &functionSpec{
+		minArgs: 2,
+		maxArgs: 4,
+		lazy: func(e *evaluator, nodes []*Node) (Value, error) {
+			if nodes[0].Type == Union {
+				// INDEX((A1:A3,C1:C3),2,1,2) picks the area by area_num
+				return e.resolveReference(e.indexReference(nodes))
+			}
+			values, err := e.evalArguments(nodes)
+			if err != nil {
+				return Value{}, err
+			}
+			return index(&arguments{values: values}), nil
+		},
+	})
+	registerArray("MATCH", 2, 3, func(args *arguments) Value {
+		value, matchType := scalar(args.value(0)), args.optNumber(2, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if value.Type == ErrorValue {
+			return value
+		}
+		cells, ok := vector(args.value(1))
+		if !ok {
+			return NewError(NAError)
+		}
+		var index int
+		switch {
+		case matchType == 0:
+			index = exactMatch(value, cells, false)
+		case matchType > 0:
+			index = approximateMatch(value, cells, false)
+		default:
+			index = approximateMatch(value, cells, true)
+		}
+		if index == -1 {
+			return NewError(NAError)
+		}
+		return NewNumber(float64(index + 1))
+	})
+	lookup := func(vertical bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			value, table, index, approximate := scalar(args.value(0)), args.array(1), math.Trunc(args.number(2)), args.optBool(3, true)
+			if args.err != "" {
+				return args.error()
+			}
+			if value.Type == ErrorValue {
+				return value
+			}
+			if !vertical {
+				table = transpose(table)
+			}
+			if index < 1 {
+				return NewError(ValueError)
+			}
+			if len(table) == 0 || int(index) > len(table[0]) {
+				return NewError(RefError)
+			}
+			keys := make([]Value, len(table))
+			for i, row := range table {
+				keys[i] = row[0]
+			}
+			var found int
+			if approximate {
+				found = approximateMatch(value, keys, false)
+			} else {
+				found = exactMatch(value, keys, false)
+			}
+			if found == -1 {
+				return NewError(NAError)
+			}
+			return table[found][int(index)-1]
+		}
+	}
+	registerArray("VLOOKUP", 3, 4, lookup(true))
+	registerArray("HLOOKUP", 3, 4, lookup(false))
+	registerArray("LOOKUP", 2, 3, func(args *arguments) Value {
+		value := scalar(args.value(0))
+		if value.Type == ErrorValue {
+			return value
+		}
+		keys, ok := vector(args.value(1))
+		results := keys
+		if args.has(2) {
+			results, ok = vector(args.value(2))
+		} else if !ok {
+			// LOOKUP(value, array) searches the first row or column and returns the last one
+			table := args.array(1)
+			if len(table) == 0 {
+				return NewError(ValueError)
+			}
+			if len(table[0]) > len(table) {
+				table = transpose(table)
+			}
+			keys = make([]Value, len(table))
+			results = make([]Value, len(table))
+			for i, row := range table {
+				keys[i], results[i] = row[0], row[len(row)-1]
+			}
+			ok = true
+		}
+		if !ok {
+			return NewError(NAError)
+		}
+		found := approximateMatch(value, keys, false)
+		if found == -1 || found >= len(results) {
+			return NewError(NAError)
+		}
+		return results[found]
+	})
+	registerArray("XLOOKUP", 3, 6, func(args *arguments) Value {
+		value, matchMode, searchMode := scalar(args.value(0)), args.optNumber(4, 0), args.optNumber(5, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if value.Type == ErrorValue {
+			return value
+		}
+		keys, ok := vector(args.value(1))
+		if !ok {
+			return NewError(ValueError)
+		}
+		found := extendedMatch(value, keys, int(matchMode), int(searchMode))
+		if found == -1 {
+			if len(args.values) > 3 {
+				return args.value(3)
+			}
+			return NewError(NAError)
+		}
+		results := args.array(2)
+		if len(results) == len(keys) {
+			if len(results[found]) == 1 {
+				return results[found][0]
+			}
+			return NewArray([][]Value{results[found]})
+		}
+		if len(results) > 0 && len(results[0]) == len(keys) {
+			column := make([][]Value, len(results))
+			for i, row := range results {
+				column[i] = []Value{row[found]}
+			}
+			if len(column) == 1 {
+				return column[0][0]
+			}
+			return NewArray(column)
+		}
+		return NewError(ValueError)
+	})
+	registerArray("XMATCH", 2, 4, func(args *arguments) Value {
+		value, matchMode, searchMode := scalar(args.value(0)), args.optNumber(2, 0), args.optNumber(3, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if value.Type == ErrorValue {
+			return value
+		}
+		keys, ok := vector(args.value(1))
+		if !ok {
+			return NewError(ValueError)
+		}
+		found := extendedMatch(value, keys, int(matchMode), int(searchMode))
+		if found == -1 {
+			return NewError(NAError)
+		}
+		return NewNumber(float64(found + 1))
+	})
+	registerScalar("ADDRESS", 2, 5, func(args *arguments) Value {
+		row, col, absolute, a1, sheet := math.Trunc(args.number(0)), math.Trunc(args.number(1)), args.optNumber(2, 1), args.optBool(3, true), args.optText(4, "")
+		if args.err != "" {
+			return args.error()
+		}
+		if row < 1 || col < 1 || row > MaxRows || col > MaxColumns || absolute < 1 || absolute > 4 {
+			return NewError(ValueError)
+		}
+		rowAbs := absolute == 1 || absolute == 2
+		colAbs := absolute == 1 || absolute == 3
+		var text string
+		if a1 {
+			text = cellAddress{Row: int(row), Col: int(col), RowAbs: rowAbs, ColAbs: colAbs}.String()
+		} else {
+			r := strconv.Itoa(int(row))
+			if !rowAbs {
+				r = "[" + r + "]"
+			}
+			c := strconv.Itoa(int(col))
+			if !colAbs {
+				c = "[" + c + "]"
+			}
+			text = "R" + r + "C" + c
+		}
+		if sheet != "" {
+			text = quoteSheetName(sheet) + "!" + text
+		}
+		return NewString(text)
+	})
+	registerArray("TRANSPOSE", 1, 1, func(args *arguments) Value {
+		return NewArray(transpose(args.array(0)))
+	})
+	registerScalar("SEQUENCE", 1, 4, func(args *arguments) Value {
+		rows, cols, start, step := math.Trunc(args.number(0)), math.Trunc(args.optNumber(1, 1)), args.optNumber(2, 1), args.optNumber(3, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if rows < 1 || cols < 1 {
+			return NewError(CalcError)
+		}
+		if rows*cols > MaxRows {
+			return NewError(ValueError)
+		}
+		result := make([][]Value, int(rows))
+		for i := range result {
+			result[i] = make([]Value, int(cols))
+			for j := range result[i] {
+				result[i][j] = NewNumber(start + step*float64(i*int(cols)+j))
+			}
+		}
+		return NewArray(result)
+	})
+	registerArray("UNIQUE", 1, 3, func(args *arguments) Value {
+		rows, byCol, exactlyOnce := args.array(0), args.optBool(1, false), args.optBool(2, false)
+		if args.err != "" {
+			return args.error()
+		}
+		if byCol {
+			rows = transpose(rows)
+		}
+		counts := map[string]int{}
+		keys := make([]string, len(rows))
+		for i, row := range rows {
+			keys[i] = strings.ToLower(NewArray([][]Value{row}).String())
+			counts[keys[i]]++
+		}
+		var result [][]Value
+		seen := map[string]bool{}
+		for i, row := range rows {
+			if seen[keys[i]] || (exactlyOnce && counts[keys[i]] != 1) {
+				continue
+			}
+			seen[keys[i]] = true
+			result = append(result, row)
+		}
+		if len(result) == 0 {
+			return NewError(CalcError)
+		}
+		if byCol {
+			result = transpose(result)
+		}
+		return NewArray(result)
+	})
+	registerArray("SORT", 1, 4, func(args *arguments) Value {
+		rows, index, order, byCol := args.array(0), math.Trunc(args.optNumber(1, 1)), args.optNumber(2, 1), args.optBool(3, false)
+		if args.err != "" {
+			return args.error()
+		}
+		if byCol {
+			rows = transpose(rows)
+		}
+		if index < 1 || (len(rows) > 0 && int(index) > len(rows[0])) || (order != 1 && order != -1) {
+			return NewError(ValueError)
+		}
+		sorted := append([][]Value{}, rows...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			result := compareValues(sorted[i][int(index)-1], sorted[j][int(index)-1])
+			if order < 0 {
+				return result > 0
+			}
+			return result < 0
+		})
+		if byCol {
+			sorted = transpose(sorted)
+		}
+		return NewArray(sorted)
+	})
+	registerArray("FILTER", 2, 3, func(args *arguments) Value {
+		rows, include := args.array(0), args.array(1)
+		if len(rows) == 0 || len(include) == 0 {
+			return NewError(CalcError)
+		}
+		var result [][]Value
+		if len(include) == len(rows) && len(include[0]) == 1 {
+			for i, row := range rows {
+				if keep, code := toBool(include[i][0]); code != "" {
+					return NewError(code)
+				} else if keep {
+					result = append(result, row)
+				}
+			}
+		} else if len(include) == 1 && len(include[0]) == len(rows[0]) {
+			for _, row := range rows {
+				var filtered []Value
+				for j, cell := range row {
+					if keep, code := toBool(include[0][j]); code != "" {
+						return NewError(code)
+					} else if keep {
+						filtered = append(filtered, cell)
+					}
+				}
+				if len(filtered) > 0 {
+					result = append(result, filtered)
+				}
+			}
+		} else {
+			return NewError(ValueError)
+		}
+		if len(result) == 0 {
+			if len(args.values) > 2 {
+				return args.value(2)
+			}
+			return NewError(CalcError)
+		}
+		return NewArray(result)
+	})
+}
+
+// position implements ROW() and COLUMN().
+func (e *evaluator) position(nodes []*Node, row bool) (Value, error) {
+	if len(nodes) == 0 {
+		context, ok := e.ctx.(PositionContext)
+		if !ok {
+			return NewError(ValueError), nil
+		}
+		r, c := context.Position()
+		if row {
+			return NewNumber(float64(r)), nil
+		}
+		return NewNumber(float64(c)), nil
+	}
+	token, errorValue, err := e.reference(nodes[0])
+	if err != nil || token == nil {
+		return errorValue, err
+	}
+	start, end, ok := parseArea(token.Text)
+	if !ok {
+		return NewError(ValueError), nil
+	}
+	if row {
+		if start.Row == end.Row {
+			return NewNumber(float64(start.Row)), nil
+		}
+		result := make([][]Value, end.Row-start.Row+1)
+		for i := range result {
+			result[i] = []Value{NewNumber(float64(start.Row + i))}
+		}
+		return NewArray(result), nil
+	}
+	if start.Col == end.Col {
+		return NewNumber(float64(start.Col)), nil
+	}
+	result := make([]Value, end.Col-start.Col+1)
+	for i := range result {
+		result[i] = NewNumber(float64(start.Col + i))
+	}
+	return NewArray([][]Value{result}), nil
+}
+
+// resolveReference resolves the result of reference() through EvalContext.
+func (e *evaluator) resolveReference(token *Token, errorValue Value, err error) (Value, error) {
+	if err != nil || token == nil {
+		return errorValue, err
+	}
+	if e.ctx == nil {
+		return NewError(RefError), nil
+	}
+	if token.Type == Name {
+		return e.ctx.ResolveName(token)
+	}
+	return e.ctx.ResolveRange(token)
+}
+
+// reference returns a Range (or Name) token that node refers to. If node is not
+// a reference, it returns nil and the error value to be the result.
+func (e *evaluator) reference(node *Node) (*Token, Value, error) {
+	switch node.Type {
+	case SingleToken:
+		if node.Token.Type == Range || node.Token.Type == Name || node.Token.Type == StructuredRef {
+			return node.Token, Value{}, nil
+		}
+	case Function:
+		// reference functions are not evaluated through call()
+		if spec := lookupFunction(node.Token.Text); spec != nil {
+			if err := checkArgumentCount(node, spec); err != nil {
+				return nil, Value{}, err
+			}
+		}
+		switch strings.TrimPrefix(strings.ToUpper(node.Token.Text), "_XLFN.") {
+		case "OFFSET":
+			return e.offsetReference(node.Children)
+		case "INDIRECT":
+			return e.indirectReference(node.Children)
+		case "INDEX":
+			if isReferenceNode(node.Children[0]) || node.Children[0].Type == Union {
+				return e.indexReference(node.Children)
+			}
+		}
+	case Intersection:
+		return e.intersectionReference(node.Children[0], node.Children[1])
+	case RangeOp:
+		return e.rangeReference(node.Children[0], node.Children[1])
+	}
+	return nil, NewError(ValueError), nil
+}
+
+// rangeReference returns the smallest area that contains both references like A1:INDEX(B1:B10,5).
+func (e *evaluator) rangeReference(left, right *Node) (*Token, Value, error) {
+	a, b, errorValue, err := e.referencePair(left, right)
+	if err != nil || a == nil {
+		return nil, errorValue, err
+	}
+	aStart, aEnd, ok := parseArea(a.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	bStart, bEnd, ok := parseArea(b.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	start := cellAddress{Row: minInt(aStart.Row, bStart.Row), Col: minInt(aStart.Col, bStart.Col)}
+	end := cellAddress{Row: maxInt(aEnd.Row, bEnd.Row), Col: maxInt(aEnd.Col, bEnd.Col)}
+	result := *a
+	result.Text = areaText(start, end)
+	return &result, Value{}, nil
+}
+
+// referencePair resolves both operands of a reference operator. They should be ranges on the same sheet.
+// EvalContext resolves names only to values, so Name operands like Name1:Name2 are #VALUE!.
+func (e *evaluator) referencePair(left, right *Node) (*Token, *Token, Value, error) {
+	a, errorValue, err := e.reference(left)
+	if err != nil || a == nil {
+		return nil, nil, errorValue, err
+	}
+	b, errorValue, err := e.reference(right)
+	if err != nil || b == nil {
+		return nil, nil, errorValue, err
+	}
+	if a.Type != Range || b.Type != Range || a.LastSheet != "" || b.LastSheet != "" || !strings.EqualFold(a.Sheet, b.Sheet) || !strings.EqualFold(a.Workbook, b.Workbook) {
+		return nil, nil, NewError(ValueError), nil
+	}
+	return a, b, Value{}, nil
+}
+
+// intersectionReference returns the area in both references. It is #NULL! if they don't overlap.
+func (e *evaluator) intersectionReference(left, right *Node) (*Token, Value, error) {
+	a, b, errorValue, err := e.referencePair(left, right)
+	if err != nil || a == nil {
+		return nil, errorValue, err
+	}
+	aStart, aEnd, ok := parseArea(a.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	bStart, bEnd, ok := parseArea(b.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	start := cellAddress{Row: maxInt(aStart.Row, bStart.Row), Col: maxInt(aStart.Col, bStart.Col)}
+	end := cellAddress{Row: minInt(aEnd.Row, bEnd.Row), Col: minInt(aEnd.Col, bEnd.Col)}
+	if start.Row > end.Row || start.Col > end.Col {
+		return nil, NewError(NullError), nil
+	}
+	result := *a
+	result.Text = areaText(start, end)
+	return &result, Value{}, nil
+}
+
+func (e *evaluator) offsetReference(nodes []*Node) (*Token, Value, error) {
+	base, errorValue, err := e.reference(nodes[0])
+	if err != nil || base == nil {
+		return nil, errorValue, err
+	}
+	start, end, ok := parseArea(base.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	values := make([]Value, len(nodes)-1)
+	for i, child := range nodes[1:] {
+		value, err := e.eval(child)
+		if err != nil {
+			return nil, Value{}, err
+		}
+		values[i] = value
+	}
+	args := &arguments{values: values}
+	rows, cols := math.Trunc(args.number(0)), math.Trunc(args.number(1))
+	height := math.Trunc(args.optNumber(2, float64(end.Row-start.Row+1)))
+	width := math.Trunc(args.optNumber(3, float64(end.Col-start.Col+1)))
+	if args.err != "" {
+		return nil, args.error(), nil
+	}
+	if height == 0 || width == 0 {
+		return nil, NewError(RefError), nil
+	}
+	top, left := start.Row+int(rows), start.Col+int(cols)
+	bottom, right := top+int(height)-1, left+int(width)-1
+	if height < 0 {
+		top, bottom = bottom+1, top
+	}
+	if width < 0 {
+		left, right = right+1, left
+	}
+	if top < 1 || left < 1 || bottom > MaxRows || right > MaxColumns {
+		return nil, NewError(RefError), nil
+	}
+	result := *base
+	result.Type = Range
+	result.Text = areaText(cellAddress{Row: top, Col: left}, cellAddress{Row: bottom, Col: right})
+	return &result, Value{}, nil
+}
+
+// indexReference returns the cell (or the row or column) that INDEX(reference, row, col) points.
+// The area of a union is chosen by the 4th argument.
+func (e *evaluator) indexReference(nodes []*Node) (*Token, Value, error) {
+	values := make([]Value, len(nodes)-1)
+	for i, child := range nodes[1:] {
+		value, err := e.eval(child)
+		if err != nil {
+			return nil, Value{}, err
+		}
+		values[i] = value
+	}
+	args := &arguments{values: values}
+	row, col, area := int(math.Trunc(args.number(0))), int(math.Trunc(args.optNumber(1, 0))), int(math.Trunc(args.optNumber(2, 1)))
+	if args.err != "" {
+		return nil, args.error(), nil
+	}
+	areas := []*Node{nodes[0]}
+	if nodes[0].Type == Union {
+		areas = nodes[0].Children
+	}
+	if area < 1 || area > len(areas) {
+		return nil, NewError(RefError), nil
+	}
+	base, errorValue, err := e.reference(areas[area-1])
+	if err != nil || base == nil {
+		return nil, errorValue, err
+	}
+	start, end, ok := parseArea(base.Text)
+	if !ok {
+		return nil, NewError(RefError), nil
+	}
+	height, width := end.Row-start.Row+1, end.Col-start.Col+1
+	if !args.has(1) && height == 1 {
+		row, col = 1, row
+	}
+	if row < 0 || col < 0 || row > height || col > width {
+		return nil, NewError(RefError), nil
+	}
+	if row != 0 {
+		start.Row += row - 1
+		end.Row = start.Row
+	}
+	if col != 0 {
+		start.Col += col - 1
+		end.Col = start.Col
+	}
+	result := *base
+	result.Text = areaText(cellAddress{Row: start.Row, Col: start.Col}, cellAddress{Row: end.Row, Col: end.Col})
+	return &result, Value{}, nil
+}
+
+func (e *evaluator) indirectReference(nodes []*Node) (*Token, Value, error) {
+	value, err := e.eval(nodes[0])
+	if err != nil {
+		return nil, Value{}, err
+	}
+	args := &arguments{values: []Value{value}}
+	text := args.text(0)
+	if args.err != "" {
+		return nil, args.error(), nil
+	}
+	tokens, err := Tokenize(text)
+	if err != nil || len(tokens) != 1 || (tokens[0].Type != Range && tokens[0].Type != Name && tokens[0].Type != StructuredRef) {
+		return nil, NewError(RefError), nil
+	}
+	return tokens[0], Value{}, nil
+}
+
+// areaText writes the area like "A1:B3". Whole columns and rows are written like "A:B" and "1:3".
+func areaText(start, end cellAddress) string {
+	if start == end {
+		return start.String()
+	}
+	if start.Row == 1 && end.Row == MaxRows {
+		start.Row, end.Row = 0, 0
+	} else if start.Col == 1 && end.Col == MaxColumns {
+		start.Col, end.Col = 0, 0
+	}
+	return start.String() + ":" + end.String()
+}
+
+func transpose(rows [][]Value) [][]Value {
+	if len(rows) == 0 {
+		return rows
+	}
+	result := make([][]Value, len(rows[0]))
+	for j := range result {
+		result[j] = make([]Value, len(rows))
+		for i := range rows {
+			result[j][i] = rows[i][j]
+		}
+	}
+	return result
+}
+
+// vector returns elements of a single row or column array.
+func vector(v Value) ([]Value, bool) {
+	rows := toRows(v)
+	if len(rows) == 1 {
+		return rows[0], true
+	}
+	if len(rows) > 0 && len(rows[0]) == 1 {
+		result := make([]Value, len(rows))
+		for i, row := range rows {
+			result[i] = row[0]
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// exactMatch returns the index of the first cell that equals value. Text is compared
+// case-insensitively with wildcards.
+func exactMatch(value Value, cells []Value, reverse bool) int {
+	wildcard := value.Type == StringValue && strings.ContainsAny(value.Text, "*?~")
+	for k := range cells {
+		i := k
+		if reverse {
+			i = len(cells) - 1 - k
+		}
+		cell := cells[i]
+		if cell.Type != value.Type {
+			continue
+		}
+		if wildcard {
+			if wildcardMatch(value.Text, cell.Text) {
+				return i
+			}
+		} else if compareValues(cell, value) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// approximateMatch returns the index of the last cell that is less than or equal to value
+// in sorted cells (or greater than or equal to value in descending cells).
+func approximateMatch(value Value, cells []Value, descending bool) int {
+	found := -1
+	for i, cell := range cells {
+		if cell.Type != value.Type {
+			continue
+		}
+		result := compareValues(cell, value)
+		if descending {
+			result = -result
+		}
+		if result > 0 {
+			break
+		}
+		found = i
+		if result == 0 && descending {
+			break
+		}
+	}
+	return found
+}
+
+// extendedMatch implements match_mode and search_mode of XLOOKUP() and XMATCH().
+func extendedMatch(value Value, cells []Value, matchMode, searchMode int) int {
+	reverse := searchMode < 0
+	switch matchMode {
+	case 0:
+		found := -1
+		for k := range cells {
+			i := k
+			if reverse {
+				i = len(cells) - 1 - k
+			}
+			if cells[i].Type == value.Type && compareValues(cells[i], value) == 0 {
+				found = i
+				break
+			}
+		}
+		return found
+	case 2:
+		return exactMatch(value, cells, reverse)
+	case -1, 1:
+		found := -1
+		for k := range cells {
+			i := k
+			if reverse {
+				i = len(cells) - 1 - k
+			}
+			cell := cells[i]
+			if cell.Type != value.Type {
+				continue
+			}
+			result := compareValues(cell, value)
+			if result == 0 {
+				return i
+			}
+			if result*matchMode < 0 {
+				continue
+			}
+			if found == -1 || compareValues(cell, cells[found])*matchMode < 0 {
+				found = i
+			}
+		}
+		return found
+	}
+	return -1
+}
@@ -0,0 +1,887 @@
+package xlsxformula
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	math1 := func(name string, f func(x float64) float64) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			x := args.number(0)
+			if args.err != "" {
+				return args.error()
+			}
+			return numberResult(f(x))
+		})
+	}
+	// functions that returns #DIV/0! when the result is infinite
+	reciprocal := func(name string, f func(x float64) float64) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			x := args.number(0)
+			if args.err != "" {
+				return args.error()
+			}
+			result := f(x)
+			if math.IsInf(result, 0) || math.IsNaN(result) {
+				return NewError(Div0Error)
+			}
+			return NewNumber(result)
+		})
+	}
+	math1("ABS", math.Abs)
+	math1("ACOS", math.Acos)
+	math1("ACOSH", math.Acosh)
+	math1("ACOT", func(x float64) float64 { return math.Pi/2 - math.Atan(x) })
+	math1("ASIN", math.Asin)
+	math1("ASINH", math.Asinh)
+	math1("ATAN", math.Atan)
+	math1("ATANH", math.Atanh)
+	math1("COS", math.Cos)
+	math1("COSH", math.Cosh)
+	math1("DEGREES", func(x float64) float64 { return x * 180 / math.Pi })
+	math1("EXP", math.Exp)
+	math1("INT", math.Floor)
+	math1("RADIANS", func(x float64) float64 { return x * math.Pi / 180 })
+	math1("SIGN", func(x float64) float64 {
+		if x > 0 {
+			return 1
+		} else if x < 0 {
+			return -1
+		}
+		return 0
+	})
+	math1("SIN", math.Sin)
+	math1("SINH", math.Sinh)
+	math1("SQRT", math.Sqrt)
+	math1("SQRTPI", func(x float64) float64 { return math.Sqrt(x * math.Pi) })
+	math1("TAN", math.Tan)
+	math1("TANH", math.Tanh)
+	math1("LN", func(x float64) float64 {
+		if x <= 0 {
+			return math.NaN()
+		}
+		return math.Log(x)
+	})
+	math1("LOG10", func(x float64) float64 {
+		if x <= 0 {
+			return math.NaN()
+		}
+		return math.Log10(x)
+	})
+	math1("ACOTH", func(x float64) float64 {
+		if math.Abs(x) <= 1 {
+			return math.NaN()
+		}
+		return 0.5 * math.Log((x+1)/(x-1))
+	})
+	reciprocal("COT", func(x float64) float64 { return 1 / math.Tan(x) })
+	reciprocal("COTH", func(x float64) float64 { return 1 / math.Tanh(x) })
+	reciprocal("CSC", func(x float64) float64 { return 1 / math.Sin(x) })
+	reciprocal("CSCH", func(x float64) float64 { return 1 / math.Sinh(x) })
+	reciprocal("SEC", func(x float64) float64 { return 1 / math.Cos(x) })
+	reciprocal("SECH", func(x float64) float64 { return 1 / math.Cosh(x) })
+
+	registerScalar("ATAN2", 2, 2, func(args *arguments) Value {
+		x, y := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if x == 0 && y == 0 {
+			return NewError(Div0Error)
+		}
+		return NewNumber(math.Atan2(y, x))
+	})
+	registerScalar("PI", 0, 0, func(args *arguments) Value {
+		return NewNumber(math.Pi)
+	})
+	registerScalar("POWER", 2, 2, func(args *arguments) Value {
+		return arithmetic("^", args.value(0), args.value(1))
+	})
+	registerScalar("LOG", 1, 2, func(args *arguments) Value {
+		x, base := args.number(0), args.optNumber(1, 10)
+		if args.err != "" {
+			return args.error()
+		}
+		if x <= 0 || base <= 0 {
+			return NewError(NumError)
+		}
+		if base == 1 {
+			return NewError(Div0Error)
+		}
+		return numberResult(math.Log(x) / math.Log(base))
+	})
+	registerScalar("MOD", 2, 2, func(args *arguments) Value {
+		n, d := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if d == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(n - d*math.Floor(n/d))
+	})
+	registerScalar("QUOTIENT", 2, 2, func(args *arguments) Value {
+		n, d := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if d == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(math.Trunc(n / d))
+	})
+
+	// rounding
+	registerScalar("ROUND", 2, 2, func(args *arguments) Value {
+		x, digits := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(roundDigits(x, digits, roundHalfAwayFromZero))
+	})
+	registerScalar("ROUNDUP", 2, 2, func(args *arguments) Value {
+		x, digits := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(roundDigits(x, digits, roundAwayFromZero))
+	})
+	registerScalar("ROUNDDOWN", 2, 2, func(args *arguments) Value {
+		x, digits := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(roundDigits(x, digits, math.Trunc))
+	})
+	registerScalar("TRUNC", 1, 2, func(args *arguments) Value {
+		x, digits := args.number(0), args.optNumber(1, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(roundDigits(x, digits, math.Trunc))
+	})
+	registerScalar("MROUND", 2, 2, func(args *arguments) Value {
+		x, multiple := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if multiple == 0 {
+			return NewNumber(0)
+		}
+		if (x > 0 && multiple < 0) || (x < 0 && multiple > 0) {
+			return NewError(NumError)
+		}
+		return numberResult(roundHalfAwayFromZero(roundSignificant(x/multiple)) * multiple)
+	})
+	registerScalar("CEILING", 2, 2, func(args *arguments) Value {
+		x, significance := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if significance == 0 {
+			return NewNumber(0)
+		}
+		if x > 0 && significance < 0 {
+			return NewError(NumError)
+		}
+		if x < 0 && significance < 0 {
+			return numberResult(-math.Ceil(roundSignificant(-x/-significance)) * -significance)
+		}
+		return numberResult(math.Ceil(roundSignificant(x/significance)) * significance)
+	})
+	registerScalar("FLOOR", 2, 2, func(args *arguments) Value {
+		x, significance := args.number(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if significance == 0 {
+			if x == 0 {
+				return NewNumber(0)
+			}
+			return NewError(Div0Error)
+		}
+		if x > 0 && significance < 0 {
+			return NewError(NumError)
+		}
+		if x < 0 && significance < 0 {
+			return numberResult(-math.Floor(roundSignificant(-x/-significance)) * -significance)
+		}
+		return numberResult(math.Floor(roundSignificant(x/significance)) * significance)
+	})
+	ceilingMath := func(args *arguments) Value {
+		x, significance, mode := args.number(0), math.Abs(args.optNumber(1, 1)), args.optNumber(2, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if significance == 0 {
+			return NewNumber(0)
+		}
+		if x < 0 && mode != 0 {
+			return numberResult(-math.Ceil(roundSignificant(-x/significance)) * significance)
+		}
+		return numberResult(math.Ceil(roundSignificant(x/significance)) * significance)
+	}
+	registerScalar("CEILING.MATH", 1, 3, ceilingMath)
+	registerScalar("CEILING.PRECISE", 1, 2, ceilingMath)
+	registerScalar("ISO.CEILING", 1, 2, ceilingMath)
+	floorMath := func(args *arguments) Value {
+		x, significance, mode := args.number(0), math.Abs(args.optNumber(1, 1)), args.optNumber(2, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if significance == 0 {
+			return NewNumber(0)
+		}
+		if x < 0 && mode != 0 {
+			return numberResult(-math.Floor(roundSignificant(-x/significance)) * significance)
+		}
+		return numberResult(math.Floor(roundSignificant(x/significance)) * significance)
+	}
+	registerScalar("FLOOR.MATH", 1, 3, floorMath)
+	registerScalar("FLOOR.PRECISE", 1, 2, floorMath)
+	registerScalar("EVEN", 1, 1, func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		result := math.Ceil(math.Abs(x)/2) * 2
+		return NewNumber(math.Copysign(result, x))
+	})
+	registerScalar("ODD", 1, 1, func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		result := math.Ceil((math.Abs(x)+1)/2)*2 - 1
+		return NewNumber(math.Copysign(result, x))
+	})
+
+	// combinatorics
+	registerScalar("FACT", 1, 1, func(args *arguments) Value {
+		n := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 {
+			return NewError(NumError)
+		}
+		return numberResult(factorial(math.Trunc(n)))
+	})
+	registerScalar("FACTDOUBLE", 1, 1, func(args *arguments) Value {
+		n := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if n < -1 {
+			return NewError(NumError)
+		}
+		result := 1.0
+		for i := math.Trunc(n); i > 1; i -= 2 {
+			result *= i
+		}
+		return numberResult(result)
+	})
+	registerScalar("COMBIN", 2, 2, func(args *arguments) Value {
+		n, k := math.Trunc(args.number(0)), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || k < 0 || n < k {
+			return NewError(NumError)
+		}
+		return numberResult(combin(n, k))
+	})
+	registerScalar("COMBINA", 2, 2, func(args *arguments) Value {
+		n, k := math.Trunc(args.number(0)), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || k < 0 || (n == 0 && k > 0) {
+			return NewError(NumError)
+		}
+		if k == 0 {
+			return NewNumber(1)
+		}
+		return numberResult(combin(n+k-1, k))
+	})
+	registerScalar("PERMUT", 2, 2, func(args *arguments) Value {
+		n, k := math.Trunc(args.number(0)), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n <= 0 || k < 0 || n < k {
+			return NewError(NumError)
+		}
+		result := 1.0
+		for i := n; i > n-k; i-- {
+			result *= i
+		}
+		return numberResult(result)
+	})
+	registerScalar("PERMUTATIONA", 2, 2, func(args *arguments) Value {
+		n, k := math.Trunc(args.number(0)), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || k < 0 {
+			return NewError(NumError)
+		}
+		return numberResult(math.Pow(n, k))
+	})
+	registerArray("MULTINOMIAL", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		sum := 0.0
+		result := 1.0
+		for _, number := range numbers {
+			number = math.Trunc(number)
+			if number < 0 {
+				return NewError(NumError)
+			}
+			sum += number
+			result *= factorial(number)
+		}
+		return numberResult(factorial(sum) / result)
+	})
+	registerArray("GCD", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			if number < 0 {
+				return NewError(NumError)
+			}
+			result = gcd(result, math.Trunc(number))
+		}
+		return NewNumber(result)
+	})
+	registerArray("LCM", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		result := 1.0
+		for _, number := range numbers {
+			if number < 0 {
+				return NewError(NumError)
+			}
+			number = math.Trunc(number)
+			if number == 0 {
+				return NewNumber(0)
+			}
+			result = result * number / gcd(result, number)
+		}
+		return numberResult(result)
+	})
+
+	// random
+	registerFunction("RAND", &functionSpec{
+		minArgs:  0,
+		maxArgs:  0,
+		volatile: true,
+		call: func(args *arguments) Value {
+			return NewNumber(rand.Float64())
+		},
+	})
+	registerFunction("RANDBETWEEN", &functionSpec{
+		minArgs:  2,
+		maxArgs:  2,
+		volatile: true,
+		scalar:   true,
+		call: func(args *arguments) Value {
+			low, high := math.Ceil(args.number(0)), math.Floor(args.number(1))
+			if args.err != "" {
+				return args.error()
+			}
+			if low > high {
+				return NewError(NumError)
+			}
+			return NewNumber(low + math.Floor(rand.Float64()*(high-low+1)))
+		},
+	})
+
+	// sums
+	registerAggregate("SUM", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		return numberResult(sum(numbers))
+	})
+	registerAggregate("SUMSQ", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			result += number * number
+		}
+		return numberResult(result)
+	})
+	registerAggregate("PRODUCT", 1, -1, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values, false)
+		if code != "" {
+			return NewError(code)
+		}
+		if len(numbers) == 0 {
+			return NewNumber(0)
+		}
+		result := 1.0
+		for _, number := range numbers {
+			result *= number
+		}
+		return numberResult(result)
+	})
+	registerArray("SUMPRODUCT", 1, -1, func(args *arguments) Value {
+		rows, cols := arraySize(args.values[0])
+		for _, value := range args.values[1:] {
+			r, c := arraySize(value)
+			if r != rows || c != cols {
+				return NewError(ValueError)
+			}
+		}
+		result := 0.0
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				product := 1.0
+				for _, value := range args.values {
+					cell, _ := broadcast(value, i, j)
+					switch cell.Type {
+					case ErrorValue:
+						return cell
+					case NumberValue:
+						product *= cell.Number
+					default:
+						product = 0
+					}
+				}
+				result += product
+			}
+		}
+		return numberResult(result)
+	})
+	sumOfPairs := func(name string, f func(x, y float64) float64) {
+		registerArray(name, 2, 2, func(args *arguments) Value {
+			xs, ys := flatten(args.values[0]), flatten(args.values[1])
+			if len(xs) != len(ys) {
+				return NewError(NAError)
+			}
+			result := 0.0
+			for i := range xs {
+				if xs[i].Type == ErrorValue {
+					return xs[i]
+				}
+				if ys[i].Type == ErrorValue {
+					return ys[i]
+				}
+				if xs[i].Type == NumberValue && ys[i].Type == NumberValue {
+					result += f(xs[i].Number, ys[i].Number)
+				}
+			}
+			return numberResult(result)
+		})
+	}
+	sumOfPairs("SUMX2MY2", func(x, y float64) float64 { return x*x - y*y })
+	sumOfPairs("SUMX2PY2", func(x, y float64) float64 { return x*x + y*y })
+	sumOfPairs("SUMXMY2", func(x, y float64) float64 { return (x - y) * (x - y) })
+	registerArray("SUMIF", 2, 3, func(args *arguments) Value {
+		cells := flatten(args.values[0])
+		condition := parseCriteria(args.values[1])
+		targets := cells
+		if args.has(2) {
+			targets = flatten(args.values[2])
+		}
+		result := 0.0
+		for i, cell := range cells {
+			if i >= len(targets) || !condition.match(cell) {
+				continue
+			}
+			if targets[i].Type == ErrorValue {
+				return targets[i]
+			}
+			if targets[i].Type == NumberValue {
+				result += targets[i].Number
+			}
+		}
+		return numberResult(result)
+	})
+	registerArray("SUMIFS", 3, -1, func(args *arguments) Value {
+		targets := flatten(args.values[0])
+		matched, code := matchCriteria(args.values[1:], len(targets))
+		if code != "" {
+			return NewError(code)
+		}
+		result := 0.0
+		for i, target := range targets {
+			if !matched[i] {
+				continue
+			}
+			if target.Type == ErrorValue {
+				return target
+			}
+			if target.Type == NumberValue {
+				result += target.Number
+			}
+		}
+		return numberResult(result)
+	})
+	registerArray("SERIESSUM", 4, 4, func(args *arguments) Value {
+		x, n, m := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		coefficients, code := collectNumbers(args.values[3:], false)
+		if code != "" {
+			return NewError(code)
+		}
+		result := 0.0
+		for i, coefficient := range coefficients {
+			result += coefficient * math.Pow(x, n+float64(i)*m)
+		}
+		return numberResult(result)
+	})
+	registerLazy("SUBTOTAL", 2, -1, func(e *evaluator, nodes []*Node) (Value, error) {
+		function, err := e.eval(nodes[0])
+		if err != nil {
+			return Value{}, err
+		}
+		number, code := toNumber(function)
+		if code != "" {
+			return NewError(code), nil
+		}
+		name, ok := subtotalFunctions[int(number)%100]
+		if !ok {
+			return NewError(ValueError), nil
+		}
+		return e.call(&Node{
+			Type:     Function,
+			Token:    &Token{Type: Name, Text: name},
+			Children: nodes[1:],
+		})
+	})
+
+	// numeral systems
+	registerScalar("ROMAN", 1, 2, func(args *arguments) Value {
+		n := math.Trunc(args.number(0))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || n > 3999 {
+			return NewError(ValueError)
+		}
+		return NewString(toRoman(int(n)))
+	})
+	registerScalar("ARABIC", 1, 1, func(args *arguments) Value {
+		text := strings.ToUpper(strings.TrimSpace(args.text(0)))
+		if args.err != "" {
+			return args.error()
+		}
+		negative := strings.HasPrefix(text, "-")
+		text = strings.TrimPrefix(text, "-")
+		digits := map[byte]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000}
+		result := 0
+		for i := 0; i < len(text); i++ {
+			digit, ok := digits[text[i]]
+			if !ok {
+				return NewError(ValueError)
+			}
+			if i+1 < len(text) && digits[text[i+1]] > digit {
+				result -= digit
+			} else {
+				result += digit
+			}
+		}
+		if negative {
+			result = -result
+		}
+		return NewNumber(float64(result))
+	})
+	registerScalar("BASE", 2, 3, func(args *arguments) Value {
+		n, radix, length := math.Trunc(args.number(0)), math.Trunc(args.number(1)), args.optNumber(2, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || n >= 1<<53 || radix < 2 || radix > 36 || length < 0 {
+			return NewError(NumError)
+		}
+		text := strings.ToUpper(strconv.FormatInt(int64(n), int(radix)))
+		for len(text) < int(length) {
+			text = "0" + text
+		}
+		return NewString(text)
+	})
+	registerScalar("DECIMAL", 2, 2, func(args *arguments) Value {
+		text, radix := args.text(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if radix < 2 || radix > 36 {
+			return NewError(NumError)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(text), int(radix), 64)
+		if err != nil {
+			return NewError(NumError)
+		}
+		return NewNumber(float64(n))
+	})
+	radixes := map[string]int{"BIN": 2, "OCT": 8, "DEC": 10, "HEX": 16}
+	for fromName, from := range radixes {
+		for toName, to := range radixes {
+			if from == to || (from == 10 && to == 10) {
+				continue
+			}
+			registerRadixConversion(fromName+"2"+toName, from, to)
+		}
+	}
+	registerScalar("BITAND", 2, 2, func(args *arguments) Value {
+		return bitOperation(args, func(a, b uint64) uint64 { return a & b })
+	})
+	registerScalar("BITOR", 2, 2, func(args *arguments) Value {
+		return bitOperation(args, func(a, b uint64) uint64 { return a | b })
+	})
+	registerScalar("BITXOR", 2, 2, func(args *arguments) Value {
+		return bitOperation(args, func(a, b uint64) uint64 { return a ^ b })
+	})
+	registerScalar("BITLSHIFT", 2, 2, func(args *arguments) Value {
+		n, shift := args.number(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || n >= 1<<48 || n != math.Trunc(n) || math.Abs(shift) > 53 {
+			return NewError(NumError)
+		}
+		result := n * math.Pow(2, shift)
+		if shift < 0 {
+			result = math.Floor(result)
+		}
+		if result >= 1<<48 {
+			return NewError(NumError)
+		}
+		return NewNumber(result)
+	})
+	registerScalar("BITRSHIFT", 2, 2, func(args *arguments) Value {
+		n, shift := args.number(0), math.Trunc(args.number(1))
+		if args.err != "" {
+			return args.error()
+		}
+		if n < 0 || n >= 1<<48 || n != math.Trunc(n) || math.Abs(shift) > 53 {
+			return NewError(NumError)
+		}
+		result := math.Floor(n / math.Pow(2, shift))
+		if result >= 1<<48 {
+			return NewError(NumError)
+		}
+		return NewNumber(result)
+	})
+	registerScalar("DELTA", 1, 2, func(args *arguments) Value {
+		a, b := args.number(0), args.optNumber(1, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if a == b {
+			return NewNumber(1)
+		}
+		return NewNumber(0)
+	})
+	registerScalar("GESTEP", 1, 2, func(args *arguments) Value {
+		n, step := args.number(0), args.optNumber(1, 0)
+		if args.err != "" {
+			return args.error()
+		}
+		if n >= step {
+			return NewNumber(1)
+		}
+		return NewNumber(0)
+	})
+}
+
+var subtotalFunctions map[int]string = map[int]string{
+	1:  "AVERAGE",
+	2:  "COUNT",
+	3:  "COUNTA",
+	4:  "MAX",
+	5:  "MIN",
+	6:  "PRODUCT",
+	7:  "STDEV",
+	8:  "STDEVP",
+	9:  "SUM",
+	10: "VAR",
+	11: "VARP",
+}
+
+// roundSignificant rounds x to 15 significant digits to hide binary floating point errors
+// like Excel does (2.675*100 is 267.49999999999997).
+func roundSignificant(x float64) float64 {
+	if x == 0 || math.IsInf(x, 0) || math.IsNaN(x) {
+		return x
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(x, 'g', 15, 64), 64)
+	if err != nil {
+		return x
+	}
+	return rounded
+}
+
+func roundDigits(x, digits float64, round func(float64) float64) float64 {
+	scale := math.Pow(10, math.Trunc(digits))
+	return round(roundSignificant(x*scale)) / scale
+}
+
+func roundHalfAwayFromZero(x float64) float64 {
+	return math.Round(x)
+}
+
+func roundAwayFromZero(x float64) float64 {
+	if x < 0 {
+		return -math.Ceil(-x)
+	}
+	return math.Ceil(x)
+}
+
+func factorial(n float64) float64 {
+	result := 1.0
+	for i := 2.0; i <= n; i++ {
+		result *= i
+	}
+	return result
+}
+
+func combin(n, k float64) float64 {
+	if k > n-k {
+		k = n - k
+	}
+	result := 1.0
+	for i := 1.0; i <= k; i++ {
+		result = result * (n - k + i) / i
+	}
+	return math.Round(result)
+}
+
+func gcd(a, b float64) float64 {
+	for b != 0 {
+		a, b = b, math.Mod(a, b)
+	}
+	return a
+}
+
+func sum(numbers []float64) float64 {
+	result := 0.0
+	for _, number := range numbers {
+		result += number
+	}
+	return result
+}
+
+// matchCriteria evaluates pairs of range and criteria of SUMIFS() like functions.
+// All ranges should have size elements.
+func matchCriteria(pairs []Value, size int) ([]bool, ErrorCode) {
+	if len(pairs)%2 != 0 {
+		return nil, ValueError
+	}
+	matched := make([]bool, size)
+	for i := range matched {
+		matched[i] = true
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		cells := flatten(pairs[i])
+		if len(cells) != size {
+			return nil, ValueError
+		}
+		condition := parseCriteria(pairs[i+1])
+		for j, cell := range cells {
+			if matched[j] && !condition.match(cell) {
+				matched[j] = false
+			}
+		}
+	}
+	return matched, ""
+}
+
+func toRoman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var builder strings.Builder
+	for i, value := range values {
+		for n >= value {
+			builder.WriteString(symbols[i])
+			n -= value
+		}
+	}
+	return builder.String()
+}
+
+// registerRadixConversion registers functions like DEC2HEX. Negative numbers are
+// written as 10 digits two's complement like Excel.
+func registerRadixConversion(name string, from, to int) {
+	bits := map[int]uint{2: 10, 8: 30, 16: 40, 10: 40}
+	registerScalar(name, 1, 2, func(args *arguments) Value {
+		var n int64
+		if from == 10 {
+			number := args.number(0)
+			if args.err != "" {
+				return args.error()
+			}
+			n = int64(math.Trunc(number))
+		} else {
+			text := strings.TrimSpace(args.text(0))
+			if args.err != "" {
+				return args.error()
+			}
+			if len(text) > 10 {
+				return NewError(NumError)
+			}
+			if text == "" {
+				text = "0"
+			}
+			parsed, err := strconv.ParseUint(text, from, 64)
+			if err != nil {
+				return NewError(NumError)
+			}
+			n = int64(parsed)
+			if len(text) == 10 && parsed >= 1<<(bits[from]-1) {
+				n -= 1 << bits[from]
+			}
+		}
+		if to == 10 {
+			return NewNumber(float64(n))
+		}
+		limit := int64(1) << (bits[to] - 1)
+		if n < -limit || n >= limit {
+			return NewError(NumError)
+		}
+		if n < 0 {
+			return NewString(strings.ToUpper(strconv.FormatInt(n+limit*2, to)))
+		}
+		text := strings.ToUpper(strconv.FormatInt(n, to))
+		if args.has(1) {
+			places := math.Trunc(args.number(1))
+			if args.err != "" {
+				return args.error()
+			}
+			if places < float64(len(text)) || places > 10 {
+				return NewError(NumError)
+			}
+			text = strings.Repeat("0", int(places)-len(text)) + text
+		}
+		return NewString(text)
+	})
+}
+
+func bitOperation(args *arguments, f func(a, b uint64) uint64) Value {
+	a, b := args.number(0), args.number(1)
+	if args.err != "" {
+		return args.error()
+	}
+	if a < 0 || b < 0 || a >= 1<<48 || b >= 1<<48 || a != math.Trunc(a) || b != math.Trunc(b) {
+		return NewError(NumError)
+	}
+	return NewNumber(float64(f(uint64(a), uint64(b))))
+}
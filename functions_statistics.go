@@ -0,0 +1,809 @@
+package xlsxformula
+
+import (
+	"math"
+	"sort"
+)
+
+func init() {
+	// aggregate registers a function that is calculated from collected numbers.
+	aggregate := func(name string, includeAll bool, f func(numbers []float64) Value) {
+		registerAggregate(name, 1, -1, func(args *arguments) Value {
+			numbers, code := collectNumbers(args.values, includeAll)
+			if code != "" {
+				return NewError(code)
+			}
+			return f(numbers)
+		})
+	}
+	average := func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(sum(numbers) / float64(len(numbers)))
+	}
+	maximum := func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewNumber(0)
+		}
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Max(result, number)
+		}
+		return NewNumber(result)
+	}
+	minimum := func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewNumber(0)
+		}
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Min(result, number)
+		}
+		return NewNumber(result)
+	}
+	variance := func(sample bool) func(numbers []float64) Value {
+		return func(numbers []float64) Value {
+			n := float64(len(numbers))
+			if n == 0 || (sample && n == 1) {
+				return NewError(Div0Error)
+			}
+			if sample {
+				return numberResult(devsq(numbers) / (n - 1))
+			}
+			return numberResult(devsq(numbers) / n)
+		}
+	}
+	deviation := func(sample bool) func(numbers []float64) Value {
+		v := variance(sample)
+		return func(numbers []float64) Value {
+			result := v(numbers)
+			if result.Type != NumberValue {
+				return result
+			}
+			return NewNumber(math.Sqrt(result.Number))
+		}
+	}
+	aggregate("AVERAGE", false, average)
+	aggregate("AVERAGEA", true, average)
+	aggregate("MAX", false, maximum)
+	aggregate("MAXA", true, maximum)
+	aggregate("MIN", false, minimum)
+	aggregate("MINA", true, minimum)
+	aggregate("VAR", false, variance(true))
+	aggregate("VAR.S", false, variance(true))
+	aggregate("VARA", true, variance(true))
+	aggregate("VARP", false, variance(false))
+	aggregate("VAR.P", false, variance(false))
+	aggregate("VARPA", true, variance(false))
+	aggregate("STDEV", false, deviation(true))
+	aggregate("STDEV.S", false, deviation(true))
+	aggregate("STDEVA", true, deviation(true))
+	aggregate("STDEVP", false, deviation(false))
+	aggregate("STDEV.P", false, deviation(false))
+	aggregate("STDEVPA", true, deviation(false))
+	aggregate("DEVSQ", false, func(numbers []float64) Value {
+		return numberResult(devsq(numbers))
+	})
+	aggregate("AVEDEV", false, func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewError(NumError)
+		}
+		mean := sum(numbers) / float64(len(numbers))
+		result := 0.0
+		for _, number := range numbers {
+			result += math.Abs(number - mean)
+		}
+		return numberResult(result / float64(len(numbers)))
+	})
+	aggregate("MEDIAN", false, func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewError(NumError)
+		}
+		return NewNumber(percentile(numbers, 0.5))
+	})
+	mode := func(numbers []float64) Value {
+		counts := map[float64]int{}
+		best := 0
+		var result float64
+		for _, number := range numbers {
+			counts[number]++
+			if counts[number] > best {
+				best = counts[number]
+				result = number
+			}
+		}
+		if best < 2 {
+			return NewError(NAError)
+		}
+		return NewNumber(result)
+	}
+	aggregate("MODE", false, mode)
+	aggregate("MODE.SNGL", false, mode)
+	aggregate("GEOMEAN", false, func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewError(NumError)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			if number <= 0 {
+				return NewError(NumError)
+			}
+			result += math.Log(number)
+		}
+		return numberResult(math.Exp(result / float64(len(numbers))))
+	})
+	aggregate("HARMEAN", false, func(numbers []float64) Value {
+		if len(numbers) == 0 {
+			return NewError(NumError)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			if number <= 0 {
+				return NewError(NumError)
+			}
+			result += 1 / number
+		}
+		return numberResult(float64(len(numbers)) / result)
+	})
+	aggregate("KURT", false, func(numbers []float64) Value {
+		n := float64(len(numbers))
+		if n < 4 {
+			return NewError(Div0Error)
+		}
+		mean := sum(numbers) / n
+		s := math.Sqrt(devsq(numbers) / (n - 1))
+		if s == 0 {
+			return NewError(Div0Error)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			result += math.Pow((number-mean)/s, 4)
+		}
+		return numberResult(n*(n+1)/((n-1)*(n-2)*(n-3))*result - 3*(n-1)*(n-1)/((n-2)*(n-3)))
+	})
+	aggregate("SKEW", false, func(numbers []float64) Value {
+		n := float64(len(numbers))
+		if n < 3 {
+			return NewError(Div0Error)
+		}
+		mean := sum(numbers) / n
+		s := math.Sqrt(devsq(numbers) / (n - 1))
+		if s == 0 {
+			return NewError(Div0Error)
+		}
+		result := 0.0
+		for _, number := range numbers {
+			result += math.Pow((number-mean)/s, 3)
+		}
+		return numberResult(n / ((n - 1) * (n - 2)) * result)
+	})
+
+	// counting
+	registerAggregate("COUNT", 1, -1, func(args *arguments) Value {
+		count := 0
+		for _, value := range args.values {
+			if value.Type != ArrayValue {
+				if _, code := toNumber(value); code == "" && value.Type != BlankValue {
+					count++
+				}
+				continue
+			}
+			for _, cell := range flatten(value) {
+				if cell.Type == NumberValue {
+					count++
+				}
+			}
+		}
+		return NewNumber(float64(count))
+	})
+	registerAggregate("COUNTA", 1, -1, func(args *arguments) Value {
+		count := 0
+		for _, value := range args.values {
+			for _, cell := range flatten(value) {
+				if cell.Type != BlankValue {
+					count++
+				}
+			}
+		}
+		return NewNumber(float64(count))
+	})
+	registerArray("COUNTBLANK", 1, 1, func(args *arguments) Value {
+		count := 0
+		for _, cell := range flatten(args.values[0]) {
+			if cell.Type == BlankValue || (cell.Type == StringValue && cell.Text == "") {
+				count++
+			}
+		}
+		return NewNumber(float64(count))
+	})
+	registerArray("COUNTIF", 2, 2, func(args *arguments) Value {
+		condition := parseCriteria(args.values[1])
+		count := 0
+		for _, cell := range flatten(args.values[0]) {
+			if condition.match(cell) {
+				count++
+			}
+		}
+		return NewNumber(float64(count))
+	})
+	registerArray("COUNTIFS", 2, -1, func(args *arguments) Value {
+		matched, code := matchCriteria(args.values, len(flatten(args.values[0])))
+		if code != "" {
+			return NewError(code)
+		}
+		count := 0
+		for _, m := range matched {
+			if m {
+				count++
+			}
+		}
+		return NewNumber(float64(count))
+	})
+	registerArray("AVERAGEIF", 2, 3, func(args *arguments) Value {
+		cells := flatten(args.values[0])
+		condition := parseCriteria(args.values[1])
+		targets := cells
+		if args.has(2) {
+			targets = flatten(args.values[2])
+		}
+		var numbers []float64
+		for i, cell := range cells {
+			if i >= len(targets) || !condition.match(cell) {
+				continue
+			}
+			if targets[i].Type == ErrorValue {
+				return targets[i]
+			}
+			if targets[i].Type == NumberValue {
+				numbers = append(numbers, targets[i].Number)
+			}
+		}
+		return average(numbers)
+	})
+	conditionalAggregate := func(name string, f func(numbers []float64) Value) {
+		registerArray(name, 3, -1, func(args *arguments) Value {
+			targets := flatten(args.values[0])
+			matched, code := matchCriteria(args.values[1:], len(targets))
+			if code != "" {
+				return NewError(code)
+			}
+			var numbers []float64
+			for i, target := range targets {
+				if !matched[i] {
+					continue
+				}
+				if target.Type == ErrorValue {
+					return target
+				}
+				if target.Type == NumberValue {
+					numbers = append(numbers, target.Number)
+				}
+			}
+			return f(numbers)
+		})
+	}
+	conditionalAggregate("AVERAGEIFS", average)
+	conditionalAggregate("MAXIFS", maximum)
+	conditionalAggregate("MINIFS", minimum)
+
+	// order statistics
+	registerAggregate("LARGE", 2, 2, func(args *arguments) Value {
+		return nthNumber(args, true)
+	})
+	registerAggregate("SMALL", 2, 2, func(args *arguments) Value {
+		return nthNumber(args, false)
+	})
+	rank := func(averageTies bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			x, ascending := args.number(0), args.optNumber(2, 0) != 0
+			if args.err != "" {
+				return args.error()
+			}
+			numbers, code := collectNumbers(args.values[1:2], false)
+			if code != "" {
+				return NewError(code)
+			}
+			better, same := 0, 0
+			for _, number := range numbers {
+				if number == x {
+					same++
+				} else if (number > x) != ascending {
+					better++
+				}
+			}
+			if same == 0 {
+				return NewError(NAError)
+			}
+			if averageTies {
+				return NewNumber(float64(better) + float64(same+1)/2)
+			}
+			return NewNumber(float64(better + 1))
+		}
+	}
+	registerAggregate("RANK", 2, 3, rank(false))
+	registerAggregate("RANK.EQ", 2, 3, rank(false))
+	registerAggregate("RANK.AVG", 2, 3, rank(true))
+	percentileFunction := func(exclusive bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			numbers, code := collectNumbers(args.values[:1], false)
+			if code != "" {
+				return NewError(code)
+			}
+			k := args.number(1)
+			if args.err != "" {
+				return args.error()
+			}
+			return percentileValue(numbers, k, exclusive)
+		}
+	}
+	registerAggregate("PERCENTILE", 2, 2, percentileFunction(false))
+	registerAggregate("PERCENTILE.INC", 2, 2, percentileFunction(false))
+	registerAggregate("PERCENTILE.EXC", 2, 2, percentileFunction(true))
+	quartileFunction := func(exclusive bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			numbers, code := collectNumbers(args.values[:1], false)
+			if code != "" {
+				return NewError(code)
+			}
+			quart := math.Trunc(args.number(1))
+			if args.err != "" {
+				return args.error()
+			}
+			if quart < 0 || quart > 4 || (exclusive && (quart == 0 || quart == 4)) {
+				return NewError(NumError)
+			}
+			return percentileValue(numbers, quart/4, exclusive)
+		}
+	}
+	registerAggregate("QUARTILE", 2, 2, quartileFunction(false))
+	registerAggregate("QUARTILE.INC", 2, 2, quartileFunction(false))
+	registerAggregate("QUARTILE.EXC", 2, 2, quartileFunction(true))
+	percentRank := func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values[:1], false)
+		if code != "" {
+			return NewError(code)
+		}
+		x, significance := args.number(1), args.optNumber(2, 3)
+		if args.err != "" {
+			return args.error()
+		}
+		if len(numbers) == 0 || significance < 1 {
+			return NewError(NumError)
+		}
+		sort.Float64s(numbers)
+		if x < numbers[0] || x > numbers[len(numbers)-1] {
+			return NewError(NAError)
+		}
+		n := float64(len(numbers))
+		if n == 1 {
+			return NewNumber(1)
+		}
+		var result float64
+		for i, number := range numbers {
+			if number == x {
+				result = float64(i) / (n - 1)
+				break
+			}
+			if number > x {
+				lower := numbers[i-1]
+				result = (float64(i-1) + (x-lower)/(number-lower)) / (n - 1)
+				break
+			}
+		}
+		scale := math.Pow(10, math.Trunc(significance))
+		return NewNumber(math.Floor(roundSignificant(result*scale)) / scale)
+	}
+	registerAggregate("PERCENTRANK", 2, 3, percentRank)
+	registerAggregate("PERCENTRANK.INC", 2, 3, percentRank)
+	registerAggregate("TRIMMEAN", 2, 2, func(args *arguments) Value {
+		numbers, code := collectNumbers(args.values[:1], false)
+		if code != "" {
+			return NewError(code)
+		}
+		percent := args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if percent < 0 || percent >= 1 || len(numbers) == 0 {
+			return NewError(NumError)
+		}
+		sort.Float64s(numbers)
+		trim := int(math.Floor(float64(len(numbers)) * percent / 2)) // from each side
+		return average(numbers[trim : len(numbers)-trim])
+	})
+
+	// pairs of data
+	pairs := func(name string, f func(xs, ys []float64) Value) {
+		registerArray(name, 2, 2, func(args *arguments) Value {
+			xs, ys, code := numberPairs(args.values[0], args.values[1])
+			if code != "" {
+				return NewError(code)
+			}
+			return f(xs, ys)
+		})
+	}
+	correl := func(xs, ys []float64) Value {
+		sxy, sxx, syy := covariance(xs, ys), covariance(xs, xs), covariance(ys, ys)
+		if len(xs) == 0 || sxx == 0 || syy == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(sxy / math.Sqrt(sxx*syy))
+	}
+	pairs("CORREL", correl)
+	pairs("PEARSON", correl)
+	pairs("RSQ", func(xs, ys []float64) Value {
+		r := correl(xs, ys)
+		if r.Type != NumberValue {
+			return r
+		}
+		return NewNumber(r.Number * r.Number)
+	})
+	populationCovariance := func(xs, ys []float64) Value {
+		if len(xs) == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(covariance(xs, ys) / float64(len(xs)))
+	}
+	pairs("COVAR", populationCovariance)
+	pairs("COVARIANCE.P", populationCovariance)
+	pairs("COVARIANCE.S", func(xs, ys []float64) Value {
+		if len(xs) < 2 {
+			return NewError(Div0Error)
+		}
+		return numberResult(covariance(xs, ys) / float64(len(xs)-1))
+	})
+	slope := func(ys, xs []float64) Value {
+		sxx := covariance(xs, xs)
+		if len(xs) == 0 || sxx == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(covariance(xs, ys) / sxx)
+	}
+	pairs("SLOPE", slope)
+	pairs("INTERCEPT", func(ys, xs []float64) Value {
+		b := slope(ys, xs)
+		if b.Type != NumberValue {
+			return b
+		}
+		return numberResult(mean(ys) - b.Number*mean(xs))
+	})
+	pairs("STEYX", func(ys, xs []float64) Value {
+		n := float64(len(xs))
+		sxx, syy, sxy := covariance(xs, xs), covariance(ys, ys), covariance(xs, ys)
+		if n < 3 || sxx == 0 {
+			return NewError(Div0Error)
+		}
+		return numberResult(math.Sqrt((syy - sxy*sxy/sxx) / (n - 2)))
+	})
+	forecast := func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		ys, xs, code := numberPairs(args.values[1], args.values[2])
+		if code != "" {
+			return NewError(code)
+		}
+		b := slope(ys, xs)
+		if b.Type != NumberValue {
+			return b
+		}
+		return numberResult(mean(ys) + b.Number*(x-mean(xs)))
+	}
+	registerArray("FORECAST", 3, 3, forecast)
+	registerArray("FORECAST.LINEAR", 3, 3, forecast)
+
+	// distributions
+	registerScalar("STANDARDIZE", 3, 3, func(args *arguments) Value {
+		x, mean, deviation := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if deviation <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult((x - mean) / deviation)
+	})
+	normDist := func(args *arguments) Value {
+		x, mean, deviation, cumulative := args.number(0), args.number(1), args.number(2), args.boolean(3)
+		if args.err != "" {
+			return args.error()
+		}
+		if deviation <= 0 {
+			return NewError(NumError)
+		}
+		z := (x - mean) / deviation
+		if cumulative {
+			return numberResult(normalCDF(z))
+		}
+		return numberResult(normalPDF(z) / deviation)
+	}
+	registerScalar("NORMDIST", 4, 4, normDist)
+	registerScalar("NORM.DIST", 4, 4, normDist)
+	registerScalar("NORMSDIST", 1, 1, func(args *arguments) Value {
+		z := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(normalCDF(z))
+	})
+	registerScalar("NORM.S.DIST", 2, 2, func(args *arguments) Value {
+		z, cumulative := args.number(0), args.boolean(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if cumulative {
+			return numberResult(normalCDF(z))
+		}
+		return numberResult(normalPDF(z))
+	})
+	normInv := func(args *arguments) Value {
+		p, mean, deviation := args.number(0), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if p <= 0 || p >= 1 || deviation <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult(mean + deviation*normalInverse(p))
+	}
+	registerScalar("NORMINV", 3, 3, normInv)
+	registerScalar("NORM.INV", 3, 3, normInv)
+	normSInv := func(args *arguments) Value {
+		p := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if p <= 0 || p >= 1 {
+			return NewError(NumError)
+		}
+		return numberResult(normalInverse(p))
+	}
+	registerScalar("NORMSINV", 1, 1, normSInv)
+	registerScalar("NORM.S.INV", 1, 1, normSInv)
+	exponDist := func(args *arguments) Value {
+		x, lambda, cumulative := args.number(0), args.number(1), args.boolean(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if x < 0 || lambda <= 0 {
+			return NewError(NumError)
+		}
+		if cumulative {
+			return numberResult(1 - math.Exp(-lambda*x))
+		}
+		return numberResult(lambda * math.Exp(-lambda*x))
+	}
+	registerScalar("EXPONDIST", 3, 3, exponDist)
+	registerScalar("EXPON.DIST", 3, 3, exponDist)
+	poisson := func(args *arguments) Value {
+		x, lambda, cumulative := math.Trunc(args.number(0)), args.number(1), args.boolean(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if x < 0 || lambda < 0 {
+			return NewError(NumError)
+		}
+		probability := func(k float64) float64 {
+			return math.Exp(k*math.Log(lambda) - lambda - logGamma(k+1))
+		}
+		if lambda == 0 {
+			return NewNumber(1)
+		}
+		if !cumulative {
+			return numberResult(probability(x))
+		}
+		result := 0.0
+		for k := 0.0; k <= x; k++ {
+			result += probability(k)
+		}
+		return numberResult(result)
+	}
+	registerScalar("POISSON", 3, 3, poisson)
+	registerScalar("POISSON.DIST", 3, 3, poisson)
+	binomDist := func(args *arguments) Value {
+		k, n, p, cumulative := math.Trunc(args.number(0)), math.Trunc(args.number(1)), args.number(2), args.boolean(3)
+		if args.err != "" {
+			return args.error()
+		}
+		if k < 0 || k > n || p < 0 || p > 1 {
+			return NewError(NumError)
+		}
+		probability := func(i float64) float64 {
+			return combin(n, i) * math.Pow(p, i) * math.Pow(1-p, n-i)
+		}
+		if !cumulative {
+			return numberResult(probability(k))
+		}
+		result := 0.0
+		for i := 0.0; i <= k; i++ {
+			result += probability(i)
+		}
+		return numberResult(result)
+	}
+	registerScalar("BINOMDIST", 4, 4, binomDist)
+	registerScalar("BINOM.DIST", 4, 4, binomDist)
+	registerScalar("FISHER", 1, 1, func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if x <= -1 || x >= 1 {
+			return NewError(NumError)
+		}
+		return numberResult(math.Atanh(x))
+	})
+	registerScalar("FISHERINV", 1, 1, func(args *arguments) Value {
+		y := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(math.Tanh(y))
+	})
+	registerScalar("GAMMA", 1, 1, func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if x <= 0 && x == math.Trunc(x) {
+			return NewError(NumError)
+		}
+		return numberResult(math.Gamma(x))
+	})
+	gammaLn := func(args *arguments) Value {
+		x := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if x <= 0 {
+			return NewError(NumError)
+		}
+		return numberResult(logGamma(x))
+	}
+	registerScalar("GAMMALN", 1, 1, gammaLn)
+	registerScalar("GAMMALN.PRECISE", 1, 1, gammaLn)
+	registerScalar("GAUSS", 1, 1, func(args *arguments) Value {
+		z := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(normalCDF(z) - 0.5)
+	})
+	registerScalar("PHI", 1, 1, func(args *arguments) Value {
+		z := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return numberResult(normalPDF(z))
+	})
+	registerScalar("CONFIDENCE", 3, 3, confidenceNorm)
+	registerScalar("CONFIDENCE.NORM", 3, 3, confidenceNorm)
+}
+
+func confidenceNorm(args *arguments) Value {
+	alpha, deviation, size := args.number(0), args.number(1), math.Trunc(args.number(2))
+	if args.err != "" {
+		return args.error()
+	}
+	if alpha <= 0 || alpha >= 1 || deviation <= 0 || size < 1 {
+		return NewError(NumError)
+	}
+	return numberResult(-normalInverse(alpha/2) * deviation / math.Sqrt(size))
+}
+
+func mean(numbers []float64) float64 {
+	return sum(numbers) / float64(len(numbers))
+}
+
+// devsq returns sum of squares of deviations from the mean.
+func devsq(numbers []float64) float64 {
+	if len(numbers) == 0 {
+		return 0
+	}
+	return covariance(numbers, numbers)
+}
+
+// covariance returns sum of (x - mean(x)) * (y - mean(y)).
+func covariance(xs, ys []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	meanX, meanY := mean(xs), mean(ys)
+	result := 0.0
+	for i := range xs {
+		result += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	return result
+}
+
+// numberPairs collects pairs of numbers. Pairs that have non-number values are skipped.
+func numberPairs(a, b Value) ([]float64, []float64, ErrorCode) {
+	as, bs := flatten(a), flatten(b)
+	if len(as) != len(bs) {
+		return nil, nil, NAError
+	}
+	var xs, ys []float64
+	for i := range as {
+		if as[i].Type == ErrorValue {
+			return nil, nil, as[i].Error
+		}
+		if bs[i].Type == ErrorValue {
+			return nil, nil, bs[i].Error
+		}
+		if as[i].Type == NumberValue && bs[i].Type == NumberValue {
+			xs = append(xs, as[i].Number)
+			ys = append(ys, bs[i].Number)
+		}
+	}
+	return xs, ys, ""
+}
+
+func nthNumber(args *arguments, largest bool) Value {
+	numbers, code := collectNumbers(args.values[:1], false)
+	if code != "" {
+		return NewError(code)
+	}
+	return liftCall(args.values[1:], func(k *arguments) Value {
+		n := math.Ceil(k.number(0))
+		if k.err != "" {
+			return k.error()
+		}
+		if n < 1 || int(n) > len(numbers) {
+			return NewError(NumError)
+		}
+		sorted := append([]float64{}, numbers...)
+		sort.Float64s(sorted)
+		if largest {
+			return NewNumber(sorted[len(sorted)-int(n)])
+		}
+		return NewNumber(sorted[int(n)-1])
+	})
+}
+
+// percentile returns k-th percentile with linear interpolation (PERCENTILE.INC).
+func percentile(numbers []float64, k float64) float64 {
+	sorted := append([]float64{}, numbers...)
+	sort.Float64s(sorted)
+	position := k * float64(len(sorted)-1)
+	lower := math.Floor(position)
+	if int(lower)+1 >= len(sorted) {
+		return sorted[int(lower)]
+	}
+	return sorted[int(lower)] + (position-lower)*(sorted[int(lower)+1]-sorted[int(lower)])
+}
+
+func percentileValue(numbers []float64, k float64, exclusive bool) Value {
+	n := float64(len(numbers))
+	if n == 0 || k < 0 || k > 1 {
+		return NewError(NumError)
+	}
+	if !exclusive {
+		return NewNumber(percentile(numbers, k))
+	}
+	position := k*(n+1) - 1
+	if position < 0 || position > n-1 {
+		return NewError(NumError)
+	}
+	if n == 1 {
+		return NewNumber(numbers[0])
+	}
+	return NewNumber(percentile(numbers, position/(n-1)))
+}
+
+func normalPDF(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+func normalInverse(p float64) float64 {
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+func logGamma(x float64) float64 {
+	result, _ := math.Lgamma(x)
+	return result
+}
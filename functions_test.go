@@ -0,0 +1,298 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+func column(values ...Value) Value {
+	rows := make([][]Value, len(values))
+	for i, value := range values {
+		rows[i] = []Value{value}
+	}
+	return NewArray(rows)
+}
+
+var functionTestContext mapContext = mapContext{
+	"A1":            NewNumber(10),
+	"A2":            NewString("5"),
+	"'My Sheet'!A1": NewNumber(100),
+	"Sales[Amount]": column(NewNumber(100), NewString("n/a"), NewNumber(50)),
+	"A1:A4":         column(NewNumber(10), NewString("5"), NewBool(true), NewNumber(20)),
+	"B1:B4":         column(NewString("apple"), NewString("banana"), NewString("apple"), NewString("cherry")),
+	"C1:C4":         column(NewNumber(1), NewNumber(2), NewNumber(3), NewNumber(4)),
+	"D1:D2":         column(NewNumber(1), NewError(NAError)),
+	"G1:G6":         column(NewNumber(-70000), NewNumber(12000), NewNumber(15000), NewNumber(18000), NewNumber(21000), NewNumber(26000)),
+	"Z1:Z9":         NewArray(nil),
+	"E1:F3": NewArray([][]Value{
+		{NewNumber(1), NewString("one")},
+		{NewNumber(2), NewString("two")},
+		{NewNumber(3), NewString("three")},
+	}),
+}
+
+func testFormulas(t *testing.T, tests map[string]string) {
+	for formula, expected := range tests {
+		if result := evaluateString(t, formula, functionTestContext); result != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, result)
+		}
+	}
+}
+
+func TestFunctionsAggregate(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`SUM(1, 2, 3)`:                   "6",
+		`SUM({1,2;3,4})`:                 "10",
+		`SUM({1;2;3;4} * C1:C4)`:         "30",
+		`SUM(Sales[Amount])`:             "150",
+		`SUM(A1:A4)`:                     "30",
+		`SUM(A1, A2)`:                    "10",
+		`SUM(A1, "5", TRUE)`:             "16",
+		`SUM("a")`:                       "#VALUE!",
+		`SUM(D1:D2)`:                     "#N/A",
+		`SUM(C1:C4 * 2)`:                 "20",
+		`AVERAGE(A1:A4)`:                 "15",
+		`AVERAGEA(A1:A4)`:                "7.75",
+		`COUNT(A1:A4)`:                   "2",
+		`COUNTA(A1:A4)`:                  "4",
+		`MAX(C1:C4, -1)`:                 "4",
+		`MIN(C1:C4)`:                     "1",
+		`MEDIAN(1, 3, 2, 4)`:             "2.5",
+		`PRODUCT(C1:C4)`:                 "24",
+		`SUMPRODUCT(C1:C4, C1:C4)`:       "30",
+		`STDEV(2, 4, 4, 4, 5, 5, 7, 9)`:  "2.1380899352994",
+		`STDEVP(2, 4, 4, 4, 5, 5, 7, 9)`: "2",
+		`LARGE(C1:C4, 2)`:                "3",
+		`SMALL(C1:C4, 1)`:                "1",
+		`RANK(3, C1:C4)`:                 "2",
+		`PERCENTILE(C1:C4, 0.5)`:         "2.5",
+		`SUBTOTAL(9, C1:C4)`:             "10",
+	})
+}
+
+func TestFunctionsCriteria(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`COUNTIF(B1:B4, "apple")`:                    "2",
+		`COUNTIF(B1:B4, "APPLE")`:                    "2",
+		`COUNTIF(B1:B4, "b*")`:                       "1",
+		`COUNTIF(B1:B4, "<>apple")`:                  "2",
+		`COUNTIF(C1:C4, ">2")`:                       "2",
+		`COUNTIF(C1:C4, 3)`:                          "1",
+		`SUMIF(B1:B4, "apple", C1:C4)`:               "4",
+		`SUMIF(C1:C4, ">=2")`:                        "9",
+		`SUMIFS(C1:C4, B1:B4, "apple", C1:C4, ">1")`: "3",
+		`COUNTIFS(B1:B4, "?????", C1:C4, "<3")`:      "1",
+		`AVERAGEIF(B1:B4, "apple", C1:C4)`:           "2",
+		`MAXIFS(C1:C4, B1:B4, "apple")`:              "3",
+	})
+}
+
+func TestFunctionsMath(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`ROUND(2.675, 2)`:         "2.68",
+		`ROUND(-2.5, 0)`:          "-3",
+		`ROUND(1234, -2)`:         "1200",
+		`ROUNDUP(3.2, 0)`:         "4",
+		`ROUNDDOWN(-3.9, 0)`:      "-3",
+		`INT(-3.5)`:               "-4",
+		`TRUNC(-3.5)`:             "-3",
+		`MOD(-3, 2)`:              "1",
+		`MOD(1, 0)`:               "#DIV/0!",
+		`SQRT(-1)`:                "#NUM!",
+		`ABS(C1:C4 * -1)`:         "{1;2;3;4}",
+		`POWER(2, 10)`:            "1024",
+		`CEILING(2.5, 1)`:         "3",
+		`FLOOR(-2.5, -2)`:         "-2",
+		`CEILING.MATH(-2.5, 2)`:   "-2",
+		`MROUND(10, 3)`:           "9",
+		`EVEN(1.5)`:               "2",
+		`ODD(2)`:                  "3",
+		`FACT(5)`:                 "120",
+		`COMBIN(5, 2)`:            "10",
+		`GCD(12, 18)`:             "6",
+		`LCM(4, 6)`:               "12",
+		`LOG(8, 2)`:               "3",
+		`ROMAN(1999)`:             "MCMXCIX",
+		`ARABIC("MCMXCIX")`:       "1999",
+		`DEC2BIN(-1)`:             "1111111111",
+		`HEX2DEC("FF")`:           "255",
+		`DEC2HEX(255, 4)`:         "00FF",
+		`BASE(255, 16)`:           "FF",
+		`BITAND(12, 10)`:          "8",
+		`_xlfn.CEILING.MATH(2.1)`: "3",
+	})
+}
+
+func TestFunctionsLogical(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`IF(A1 > 5, "big", "small")`:     "big",
+		`IF(FALSE, 1)`:                   "FALSE",
+		`IF(TRUE, 1/0, 1)`:               "#DIV/0!",
+		`IF(FALSE, 1/0, 1)`:              "1",
+		`IF(C1:C4 > 2, 1, 2)`:            "{2;2;1;1}",
+		`IFERROR(1/0, "error")`:          "error",
+		`IFERROR(10, 1/0)`:               "10",
+		`IFNA(D1:D2, 0)`:                 "{1;#N/A}",
+		`IFS(A1 < 5, "a", A1 < 20, "b")`: "b",
+		`SWITCH(2, 1, "one", 2, "two")`:  "two",
+		`SWITCH(3, 1, "one", "other")`:   "other",
+		`CHOOSE(2, 1/0, "second")`:       "second",
+		`AND(TRUE, 1, A1:A4)`:            "TRUE",
+		`OR(FALSE, 0)`:                   "FALSE",
+		`XOR(TRUE, TRUE, TRUE)`:          "TRUE",
+		`NOT(0)`:                         "TRUE",
+		`ISBLANK(Z9)`:                    "TRUE",
+		`ISNUMBER(A2)`:                   "FALSE",
+		`ISTEXT(A2)`:                     "TRUE",
+		`ISERROR(1/0)`:                   "TRUE",
+		`ISNA(NA())`:                     "TRUE",
+		`ISREF(A1)`:                      "TRUE",
+		`TYPE(A2)`:                       "2",
+		`ERROR.TYPE(1/0)`:                "2",
+		`N(TRUE)`:                        "1",
+	})
+}
+
+func TestFunctionsText(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`LEFT("hello", 2)`:                       "he",
+		`RIGHT("hello")`:                         "o",
+		`MID("hello", 2, 3)`:                     "ell",
+		`LEN("日本語")`:                             "3",
+		`UPPER("abc") & LOWER("DEF")`:            "ABCdef",
+		`PROPER("hello WORLD")`:                  "Hello World",
+		`TRIM("  a   b  ")`:                      "a b",
+		`SUBSTITUTE("a-b-c", "-", "+")`:          "a+b+c",
+		`SUBSTITUTE("a-b-c", "-", "+", 2)`:       "a-b+c",
+		`REPLACE("abcdef", 2, 3, "X")`:           "aXef",
+		`FIND("l", "hello")`:                     "3",
+		`FIND("L", "hello")`:                     "#VALUE!",
+		`SEARCH("L?O", "hello")`:                 "3",
+		`CONCATENATE("a", 1, TRUE)`:              "a1TRUE",
+		`CONCAT(B1:B4)`:                          "applebananaapplecherry",
+		`TEXTJOIN(",", TRUE, "a", "", "b")`:      "a,b",
+		`REPT("ab", 3)`:                          "ababab",
+		`EXACT("a", "A")`:                        "FALSE",
+		`VALUE("1,000.5")`:                       "1000.5",
+		`TEXT(1234.567, "#,##0.00")`:             "1,234.57",
+		`TEXT(0.25, "0%")`:                       "25%",
+		`TEXT(5, "000")`:                         "005",
+		`TEXT(DATE(2015, 10, 21), "yyyy-mm-dd")`: "2015-10-21",
+		`TEXT(0.75, "hh:mm")`:                    "18:00",
+		`FIXED(1234.5, 1)`:                       "1,234.5",
+		`CHAR(65) & CODE("A")`:                   "A65",
+		`TEXTBEFORE("a.b.c", ".")`:               "a",
+		`TEXTAFTER("a.b.c", ".", -1)`:            "c",
+	})
+}
+
+func TestFunctionsLookup(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`VLOOKUP(2, E1:F3, 2, FALSE)`:               "two",
+		`VLOOKUP(2.5, E1:F3, 2)`:                    "two",
+		`VLOOKUP(9, E1:F3, 2, FALSE)`:               "#N/A",
+		`HLOOKUP(2, TRANSPOSE(E1:F3), 2)`:           "two",
+		`MATCH("cherry", B1:B4, 0)`:                 "4",
+		`MATCH(3.5, C1:C4)`:                         "3",
+		`INDEX(E1:F3, 3, 2)`:                        "three",
+		`INDEX({"a","b";"c","d"}, 2, 1)`:            "c",
+		`INDEX(C1:C4, 2)`:                           "2",
+		`INDEX(E1:F3, 5, 1)`:                        "#REF!",
+		`XLOOKUP("three", F1:F3, E1:E3)`:            "#N/A",
+		`XLOOKUP(3, C1:C4, B1:B4)`:                  "apple",
+		`XLOOKUP(9, C1:C4, B1:B4, "none")`:          "none",
+		`XMATCH(2.5, C1:C4, 1)`:                     "3",
+		`LOOKUP(3, C1:C4, B1:B4)`:                   "apple",
+		`ROW(C3)`:                                   "3",
+		`COLUMN(B1:D1)`:                             "{2,3,4}",
+		`ROWS(E1:F3) & COLUMNS(E1:F3)`:              "32",
+		`ADDRESS(2, 3)`:                             "$C$2",
+		`ADDRESS(2, 3, 4, FALSE, "My Sheet")`:       "'My Sheet'!R[2]C[3]",
+		`SUM(OFFSET(C1, 1, 0, 2, 1))`:               "0",
+		`INDIRECT("'My Sheet'!A1") + 'My Sheet'!A1`: "200",
+		`INDIRECT("A1") + 1`:                        "11",
+		`TRANSPOSE(C1:C4)`:                          "{1,2,3,4}",
+		`SEQUENCE(2, 2)`:                            "{1,2;3,4}",
+		`UNIQUE(B1:B4)`:                             `{"apple";"banana";"cherry"}`,
+		`SORT(B1:B4, 1, -1)`:                        `{"cherry";"banana";"apple";"apple"}`,
+		`FILTER(C1:C4, C1:C4 > 2)`:                  "{3;4}",
+		`FILTER(Z1:Z9, Z1:Z9)`:                      "#CALC!",
+		`LOOKUP(1, Z1:Z9)`:                          "#VALUE!",
+		`VLOOKUP(1, Z1:Z9, 1)`:                      "#REF!",
+	})
+}
+
+func TestFunctionsReferenceArgumentCount(t *testing.T) {
+	for _, formula := range []string{"ROW(OFFSET())", "ROW(INDEX())", "COLUMN(INDIRECT())", "ROWS(OFFSET(A1))", "COLUMN(INDEX(A1:B2,1,1,1,1))"} {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("parse error of %s: %v", formula, err)
+			continue
+		}
+		if _, err := Evaluate(node, functionTestContext); err == nil {
+			t.Errorf("%s should be error, but nil", formula)
+		}
+	}
+}
+
+func TestFunctionsDateTime(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`DATE(1900, 1, 1)`:   "1",
+		`DATE(1900, 2, 29)`:  "60",
+		`DATE(1900, 3, 1)`:   "61",
+		`DATE(2015, 10, 21)`: "42298",
+		`DATE(2015, 13, 1)`:  "42370",
+		`YEAR(42298) & "/" & MONTH(42298) & "/" & DAY(42298)`: "2015/10/21",
+		`DAY(60)`:                        "29",
+		`WEEKDAY(42298)`:                 "4",
+		`WEEKDAY(42298, 2)`:              "3",
+		`WEEKNUM(DATE(2015, 10, 21))`:    "43",
+		`ISOWEEKNUM(DATE(2015, 10, 21))`: "43",
+		`TIME(12, 30, 0)`:                "0.520833333333333",
+		`HOUR(0.520833333333333) & MINUTE(0.520833333333333)`:  "1230",
+		`DATEVALUE("2015-10-21")`:                              "42298",
+		`YEAR("2015-10-21")`:                                   "2015",
+		`EDATE(DATE(2015, 1, 31), 1)`:                          "42063",
+		`EOMONTH(DATE(2016, 1, 15), 1)`:                        "42429",
+		`DATEDIF(DATE(2000, 2, 15), DATE(2015, 10, 21), "Y")`:  "15",
+		`DATEDIF(DATE(2000, 2, 15), DATE(2015, 10, 21), "YM")`: "8",
+		`DAYS(DATE(2015, 10, 21), DATE(2015, 1, 1))`:           "293",
+		`DAYS360(DATE(2015, 1, 31), DATE(2015, 3, 31))`:        "60",
+		`NETWORKDAYS(DATE(2015, 10, 19), DATE(2015, 10, 25))`:  "5",
+		`WORKDAY(DATE(2015, 10, 23), 1)`:                       "42303",
+		`YEARFRAC(DATE(2015, 1, 1), DATE(2015, 7, 1), 2)`:      "0.502777777777778",
+	})
+}
+
+func TestFunctionsFinancial(t *testing.T) {
+	testFormulas(t, map[string]string{
+		`ROUND(PMT(0.08/12, 10, 10000), 2)`:            "-1037.03",
+		`ROUND(FV(0.06/12, 10, -200, -500, 1), 2)`:     "2581.4",
+		`ROUND(PV(0.08/12, 240, 500), 2)`:              "-59777.15",
+		`ROUND(NPER(0.01, -100, 1000), 4)`:             "10.5886",
+		`ROUND(RATE(48, -200, 8000), 6)`:               "0.007701",
+		`ROUND(NPV(0.1, -10000, 3000, 4200, 6800), 2)`: "1188.44",
+		`ROUND(IRR(G1:G6), 4)`:                         "0.0866",
+		`ROUND(IPMT(0.1/12, 1, 36, 8000), 2)`:          "-66.67",
+		`SLN(30000, 7500, 10)`:                         "2250",
+		`ROUND(EFFECT(0.0525, 4), 6)`:                  "0.053543",
+	})
+}
+
+func TestRegisterFunction(t *testing.T) {
+	RegisterFunction("DOUBLE", 1, 1, func(args []Value) Value {
+		number, code := toNumber(args[0])
+		if code != "" {
+			return NewError(code)
+		}
+		return NewNumber(number * 2)
+	})
+	if !IsFunction("double") {
+		t.Errorf("registered function should be found")
+	}
+	if result := evaluateString(t, "DOUBLE(21)", nil); result != "42" {
+		t.Errorf("result should be 42, but %s", result)
+	}
+	if !IsVolatileFunction("NOW") || IsVolatileFunction("SUM") {
+		t.Errorf("NOW should be volatile and SUM should not")
+	}
+}
@@ -0,0 +1,641 @@
+package xlsxformula
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func init() {
+	text1 := func(name string, f func(text string) string) {
+		registerScalar(name, 1, 1, func(args *arguments) Value {
+			text := args.text(0)
+			if args.err != "" {
+				return args.error()
+			}
+			return NewString(f(text))
+		})
+	}
+	text1("LOWER", strings.ToLower)
+	text1("UPPER", strings.ToUpper)
+	text1("PROPER", properCase)
+	text1("TRIM", func(text string) string {
+		return strings.Join(strings.FieldsFunc(text, func(r rune) bool { return r == ' ' }), " ")
+	})
+	text1("CLEAN", func(text string) string {
+		return strings.Map(func(r rune) rune {
+			if r < 32 {
+				return -1
+			}
+			return r
+		}, text)
+	})
+
+	registerScalar("LEN", 1, 1, func(args *arguments) Value {
+		text := args.text(0)
+		if args.err != "" {
+			return args.error()
+		}
+		return NewNumber(float64(utf8.RuneCountInString(text)))
+	})
+	registerScalar("LEFT", 1, 2, func(args *arguments) Value {
+		text, count := []rune(args.text(0)), args.optNumber(1, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if count < 0 {
+			return NewError(ValueError)
+		}
+		if int(count) < len(text) {
+			text = text[:int(count)]
+		}
+		return NewString(string(text))
+	})
+	registerScalar("RIGHT", 1, 2, func(args *arguments) Value {
+		text, count := []rune(args.text(0)), args.optNumber(1, 1)
+		if args.err != "" {
+			return args.error()
+		}
+		if count < 0 {
+			return NewError(ValueError)
+		}
+		if int(count) < len(text) {
+			text = text[len(text)-int(count):]
+		}
+		return NewString(string(text))
+	})
+	registerScalar("MID", 3, 3, func(args *arguments) Value {
+		text, start, count := []rune(args.text(0)), args.number(1), args.number(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if start < 1 || count < 0 {
+			return NewError(ValueError)
+		}
+		first := int(start) - 1
+		if first >= len(text) {
+			return NewString("")
+		}
+		last := first + int(count)
+		if last > len(text) {
+			last = len(text)
+		}
+		return NewString(string(text[first:last]))
+	})
+	registerScalar("REPLACE", 4, 4, func(args *arguments) Value {
+		text, start, count, replacement := []rune(args.text(0)), args.number(1), args.number(2), args.text(3)
+		if args.err != "" {
+			return args.error()
+		}
+		if start < 1 || count < 0 {
+			return NewError(ValueError)
+		}
+		first := int(start) - 1
+		if first > len(text) {
+			first = len(text)
+		}
+		last := first + int(count)
+		if last > len(text) {
+			last = len(text)
+		}
+		return NewString(string(text[:first]) + replacement + string(text[last:]))
+	})
+	registerScalar("REPT", 2, 2, func(args *arguments) Value {
+		text, count := args.text(0), args.number(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if count < 0 || float64(len(text))*count > 32767 {
+			return NewError(ValueError)
+		}
+		return NewString(strings.Repeat(text, int(count)))
+	})
+	registerScalar("SUBSTITUTE", 3, 4, func(args *arguments) Value {
+		text, old, replacement := args.text(0), args.text(1), args.text(2)
+		if args.err != "" {
+			return args.error()
+		}
+		if old == "" {
+			return NewString(text)
+		}
+		if !args.has(3) {
+			return NewString(strings.Replace(text, old, replacement, -1))
+		}
+		instance := args.number(3)
+		if args.err != "" {
+			return args.error()
+		}
+		if instance < 1 {
+			return NewError(ValueError)
+		}
+		index := 0
+		for i := 1; ; i++ {
+			found := strings.Index(text[index:], old)
+			if found == -1 {
+				return NewString(text)
+			}
+			index += found
+			if i == int(instance) {
+				return NewString(text[:index] + replacement + text[index+len(old):])
+			}
+			index += len(old)
+		}
+	})
+	find := func(caseSensitive bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			needle, haystack, start := args.text(0), []rune(args.text(1)), args.optNumber(2, 1)
+			if args.err != "" {
+				return args.error()
+			}
+			if start < 1 || int(start) > len(haystack)+1 {
+				return NewError(ValueError)
+			}
+			for i := int(start) - 1; i <= len(haystack); i++ {
+				rest := string(haystack[i:])
+				if caseSensitive {
+					if strings.HasPrefix(rest, needle) {
+						return NewNumber(float64(i + 1))
+					}
+				} else if prefixWildcardMatch(needle, rest) {
+					return NewNumber(float64(i + 1))
+				}
+			}
+			return NewError(ValueError)
+		}
+	}
+	registerScalar("FIND", 2, 3, find(true))
+	registerScalar("SEARCH", 2, 3, find(false))
+	registerScalar("EXACT", 2, 2, func(args *arguments) Value {
+		a, b := args.text(0), args.text(1)
+		if args.err != "" {
+			return args.error()
+		}
+		return NewBool(a == b)
+	})
+	registerScalar("CHAR", 1, 1, func(args *arguments) Value {
+		code := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if code < 1 || code > 255 {
+			return NewError(ValueError)
+		}
+		return NewString(string(rune(int(code))))
+	})
+	registerScalar("UNICHAR", 1, 1, func(args *arguments) Value {
+		code := args.number(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if code < 1 || code > unicode.MaxRune || !utf8.ValidRune(rune(int(code))) {
+			return NewError(ValueError)
+		}
+		return NewString(string(rune(int(code))))
+	})
+	code := func(args *arguments) Value {
+		text := args.text(0)
+		if args.err != "" {
+			return args.error()
+		}
+		if text == "" {
+			return NewError(ValueError)
+		}
+		r, _ := utf8.DecodeRuneInString(text)
+		return NewNumber(float64(r))
+	}
+	registerScalar("CODE", 1, 1, code)
+	registerScalar("UNICODE", 1, 1, code)
+	registerScalar("T", 1, 1, func(args *arguments) Value {
+		value := args.value(0)
+		if value.Type == ErrorValue || value.Type == StringValue {
+			return value
+		}
+		return NewString("")
+	})
+	registerScalar("VALUE", 1, 1, func(args *arguments) Value {
+		value := args.value(0)
+		switch value.Type {
+		case ErrorValue, NumberValue:
+			return value
+		case BlankValue:
+			return NewNumber(0)
+		case StringValue:
+			if number, ok := parseNumber(strings.Replace(value.Text, ",", "", -1)); ok {
+				return NewNumber(number)
+			}
+			if serial, ok := parseDateTime(value.Text); ok {
+				return NewNumber(serial)
+			}
+		}
+		return NewError(ValueError)
+	})
+	registerScalar("NUMBERVALUE", 1, 3, func(args *arguments) Value {
+		text, decimal, group := args.text(0), args.optText(1, "."), args.optText(2, ",")
+		if args.err != "" {
+			return args.error()
+		}
+		if decimal == "" || group == "" {
+			return NewError(ValueError)
+		}
+		text = strings.Replace(text, " ", "", -1)
+		text = strings.Replace(text, group[:1], "", -1)
+		text = strings.Replace(text, decimal[:1], ".", 1)
+		if number, ok := parseNumber(text); ok {
+			return NewNumber(number)
+		}
+		return NewError(ValueError)
+	})
+	registerScalar("FIXED", 1, 3, func(args *arguments) Value {
+		number, decimals, noCommas := args.number(0), args.optNumber(1, 2), args.optBool(2, false)
+		if args.err != "" {
+			return args.error()
+		}
+		return NewString(formatFixed(number, int(decimals), !noCommas))
+	})
+	registerScalar("DOLLAR", 1, 2, func(args *arguments) Value {
+		number, decimals := args.number(0), args.optNumber(1, 2)
+		if args.err != "" {
+			return args.error()
+		}
+		text := formatFixed(math.Abs(number), int(decimals), true)
+		if number < 0 {
+			return NewString("($" + text + ")")
+		}
+		return NewString("$" + text)
+	})
+	registerScalar("TEXT", 2, 2, func(args *arguments) Value {
+		value, format := args.value(0), args.text(1)
+		if args.err != "" {
+			return args.error()
+		}
+		if value.Type == ErrorValue {
+			return value
+		}
+		return formatText(value, format)
+	})
+	registerArray("CONCATENATE", 1, -1, func(args *arguments) Value {
+		return liftCall(args.values, func(args *arguments) Value {
+			var builder strings.Builder
+			for i := range args.values {
+				builder.WriteString(args.text(i))
+			}
+			if args.err != "" {
+				return args.error()
+			}
+			return NewString(builder.String())
+		})
+	})
+	registerArray("CONCAT", 1, -1, func(args *arguments) Value {
+		var builder strings.Builder
+		for _, value := range args.values {
+			for _, cell := range flatten(value) {
+				text, code := toText(cell)
+				if code != "" {
+					return NewError(code)
+				}
+				builder.WriteString(text)
+			}
+		}
+		return NewString(builder.String())
+	})
+	registerArray("TEXTJOIN", 3, -1, func(args *arguments) Value {
+		delimiter, ignoreEmpty := args.text(0), args.boolean(1)
+		if args.err != "" {
+			return args.error()
+		}
+		var texts []string
+		for _, value := range args.values[2:] {
+			for _, cell := range flatten(value) {
+				text, code := toText(cell)
+				if code != "" {
+					return NewError(code)
+				}
+				if ignoreEmpty && text == "" {
+					continue
+				}
+				texts = append(texts, text)
+			}
+		}
+		return NewString(strings.Join(texts, delimiter))
+	})
+	textSplit := func(after bool) func(args *arguments) Value {
+		return func(args *arguments) Value {
+			text, delimiter, instance := args.text(0), args.text(1), args.optNumber(2, 1)
+			if args.err != "" {
+				return args.error()
+			}
+			if instance == 0 {
+				return NewError(ValueError)
+			}
+			if delimiter == "" {
+				if after == (instance > 0) {
+					return NewString(text)
+				}
+				return NewString("")
+			}
+			index := -1
+			if instance > 0 {
+				offset := 0
+				for i := 0; i < int(instance); i++ {
+					found := strings.Index(text[offset:], delimiter)
+					if found == -1 {
+						return NewError(NAError)
+					}
+					index = offset + found
+					offset = index + len(delimiter)
+				}
+			} else {
+				end := len(text)
+				for i := 0; i < int(-instance); i++ {
+					index = strings.LastIndex(text[:end], delimiter)
+					if index == -1 {
+						return NewError(NAError)
+					}
+					end = index
+				}
+			}
+			if after {
+				return NewString(text[index+len(delimiter):])
+			}
+			return NewString(text[:index])
+		}
+	}
+	registerScalar("TEXTBEFORE", 2, 3, textSplit(false))
+	registerScalar("TEXTAFTER", 2, 3, textSplit(true))
+}
+
+func properCase(text string) string {
+	runes := []rune(text)
+	previousLetter := false
+	for i, r := range runes {
+		if previousLetter {
+			runes[i] = unicode.ToLower(r)
+		} else {
+			runes[i] = unicode.ToUpper(r)
+		}
+		previousLetter = unicode.IsLetter(r)
+	}
+	return string(runes)
+}
+
+// prefixWildcardMatch returns true if text starts with a part that matches pattern.
+func prefixWildcardMatch(pattern, text string) bool {
+	return wildcardMatch(pattern+"*", text)
+}
+
+// formatFixed formats number with fixed decimals like FIXED(). Negative decimals
+// round to the left of the decimal point.
+func formatFixed(number float64, decimals int, commas bool) string {
+	if decimals > 127 {
+		decimals = 127
+	}
+	number = roundDigits(number, float64(decimals), roundHalfAwayFromZero)
+	if decimals < 0 {
+		decimals = 0
+	}
+	text := strconv.FormatFloat(math.Abs(number), 'f', decimals, 64)
+	if commas {
+		integer := text
+		fraction := ""
+		if index := strings.IndexByte(text, '.'); index != -1 {
+			integer, fraction = text[:index], text[index:]
+		}
+		text = groupThousands(integer) + fraction
+	}
+	if number < 0 {
+		return "-" + text
+	}
+	return text
+}
+
+func groupThousands(digits string) string {
+	var builder strings.Builder
+	for i, ch := range digits {
+		if i != 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteRune(ch)
+	}
+	return builder.String()
+}
+
+// formatText implements a subset of Excel's number formats for TEXT(): digits
+// placeholders (0, #), thousands separator, percent, scientific notation,
+// date/time codes (yyyy, mm, dd, hh, ss, AM/PM), quoted literals and sections
+// separated by ';' for positive, negative and zero.
+func formatText(value Value, format string) Value {
+	if value.Type == StringValue {
+		if number, ok := parseNumber(value.Text); ok {
+			value = NewNumber(number)
+		} else {
+			sections := splitFormatSections(format)
+			if len(sections) >= 4 {
+				return NewString(strings.Replace(unquoteFormat(sections[3]), "@", value.Text, -1))
+			}
+			return value
+		}
+	}
+	number, code := toNumber(value)
+	if code != "" {
+		return NewError(code)
+	}
+	sections := splitFormatSections(format)
+	section := sections[0]
+	if number < 0 && len(sections) >= 2 {
+		section = sections[1]
+		number = -number
+	} else if number == 0 && len(sections) >= 3 {
+		section = sections[2]
+	}
+	if strings.EqualFold(section, "General") || section == "" {
+		return NewString(formatNumber(number))
+	}
+	if isDateFormat(section) {
+		text, ok := formatDateTime(number, section)
+		if !ok {
+			return NewError(ValueError)
+		}
+		return NewString(text)
+	}
+	return NewString(formatNumberPattern(number, section))
+}
+
+func splitFormatSections(format string) []string {
+	var sections []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(format); i++ {
+		switch format[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case ';':
+			if !quoted {
+				sections = append(sections, format[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(sections, format[start:])
+}
+
+func unquoteFormat(section string) string {
+	var builder strings.Builder
+	for i := 0; i < len(section); i++ {
+		switch section[i] {
+		case '"':
+		case '\\':
+			if i+1 < len(section) {
+				i++
+				builder.WriteByte(section[i])
+			}
+		default:
+			builder.WriteByte(section[i])
+		}
+	}
+	return builder.String()
+}
+
+func isDateFormat(section string) bool {
+	quoted := false
+	for i := 0; i < len(section); i++ {
+		ch := section[i]
+		if ch == '"' {
+			quoted = !quoted
+			continue
+		}
+		if quoted {
+			continue
+		}
+		switch ch {
+		case '\\':
+			i++
+		case 'y', 'Y', 'd', 'D', 'h', 'H', 's', 'S':
+			return true
+		case 'm', 'M':
+			if !strings.ContainsAny(section, "0#?") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatNumberPattern formats number with a pattern like "#,##0.00", "0%" or "0.00E+00".
+func formatNumberPattern(number float64, pattern string) string {
+	// split literal prefix, numeric part, and literal suffix
+	var prefix, body, suffix strings.Builder
+	state := 0 // 0: prefix, 1: numeric part, 2: suffix
+	writeLiteral := func(text string) {
+		if state == 0 {
+			prefix.WriteString(text)
+		} else {
+			state = 2
+			suffix.WriteString(text)
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '"' {
+			end := strings.IndexByte(pattern[i+1:], '"')
+			if end == -1 {
+				end = len(pattern) - i - 1
+			}
+			writeLiteral(pattern[i+1 : i+1+end])
+			i += end + 1
+			continue
+		}
+		if ch == '\\' && i+1 < len(pattern) {
+			i++
+			writeLiteral(pattern[i : i+1])
+			continue
+		}
+		numeric := strings.IndexByte("0#?.,", ch) != -1
+		if state == 1 && (ch == 'E' || ch == 'e') {
+			numeric = true
+		}
+		if state == 1 && (ch == '+' || ch == '-') && (pattern[i-1] == 'E' || pattern[i-1] == 'e') {
+			numeric = true
+		}
+		if numeric && state < 2 {
+			state = 1
+			body.WriteByte(ch)
+		} else {
+			writeLiteral(pattern[i : i+1])
+		}
+	}
+	literals := prefix.String() + suffix.String()
+	percent := strings.Count(literals, "%")
+	for i := 0; i < percent; i++ {
+		number *= 100
+	}
+	numeric := body.String()
+	// trailing commas scale by 1000
+	for strings.HasSuffix(numeric, ",") {
+		numeric = numeric[:len(numeric)-1]
+		number /= 1000
+	}
+	var text string
+	if index := strings.IndexAny(numeric, "Ee"); index != -1 {
+		mantissaPattern := numeric[:index]
+		exponentDigits := len(strings.TrimLeft(numeric[index+1:], "+-"))
+		decimals := 0
+		if dot := strings.IndexByte(mantissaPattern, '.'); dot != -1 {
+			decimals = len(mantissaPattern) - dot - 1
+		}
+		formatted := strconv.FormatFloat(number, 'E', decimals, 64)
+		parts := strings.SplitN(formatted, "E", 2)
+		exponent, _ := strconv.Atoi(parts[1])
+		sign := "+"
+		if exponent < 0 {
+			sign = "-"
+			exponent = -exponent
+		}
+		exponentText := strconv.Itoa(exponent)
+		for len(exponentText) < exponentDigits {
+			exponentText = "0" + exponentText
+		}
+		text = parts[0] + "E" + sign + exponentText
+	} else {
+		integerPattern, fractionPattern := numeric, ""
+		if dot := strings.IndexByte(numeric, '.'); dot != -1 {
+			integerPattern, fractionPattern = numeric[:dot], numeric[dot+1:]
+		}
+		comma := strings.Contains(integerPattern, ",")
+		integerPattern = strings.Replace(integerPattern, ",", "", -1)
+		rounded := roundDigits(math.Abs(number), float64(len(fractionPattern)), roundHalfAwayFromZero)
+		formatted := strconv.FormatFloat(rounded, 'f', len(fractionPattern), 64)
+		integer, fraction := formatted, ""
+		if dot := strings.IndexByte(formatted, '.'); dot != -1 {
+			integer, fraction = formatted[:dot], formatted[dot+1:]
+		}
+		minimum := strings.Count(integerPattern, "0")
+		if integer == "0" && minimum == 0 {
+			integer = ""
+		}
+		for len(integer) < minimum {
+			integer = "0" + integer
+		}
+		if comma {
+			integer = groupThousands(integer)
+		}
+		// optional fraction digits (#) drop trailing zeros
+		required := strings.LastIndexByte(fractionPattern, '0') + 1
+		for len(fraction) > required && strings.HasSuffix(fraction, "0") {
+			fraction = fraction[:len(fraction)-1]
+		}
+		text = integer
+		if fractionPattern != "" && (fraction != "" || strings.HasSuffix(numeric, ".")) {
+			text += "." + fraction
+		} else if strings.HasSuffix(numeric, ".") {
+			text += "."
+		}
+		if number < 0 && rounded != 0 {
+			text = "-" + text
+		}
+	}
+	return prefix.String() + text + suffix.String()
+}
@@ -0,0 +1,661 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CellRef is a position of a cell in a workbook. Row and Col are 1 origin.
+type CellRef struct {
+	Sheet string
+	Row   int
+	Col   int
+}
+
+// ParseCellRef creates CellRef from sheet name and A1 style address like "B3".
+func ParseCellRef(sheet, cell string) (CellRef, error) {
+	address, ok := parseCellAddress(cell)
+	if !ok {
+		return CellRef{}, fmt.Errorf("Invalid cell address '%s'", cell)
+	}
+	return CellRef{Sheet: sheet, Row: address.Row, Col: address.Col}, nil
+}
+
+func (c CellRef) String() string {
+	return (&Token{Sheet: c.Sheet}).SheetPrefix() + cellAddress{Row: c.Row, Col: c.Col}.String()
+}
+
+func (c CellRef) key() CellRef {
+	return CellRef{Sheet: strings.ToUpper(c.Sheet), Row: c.Row, Col: c.Col}
+}
+
+// Area is a rectangle of cells on a sheet. A whole column like A:A is an area from row 1 to MaxRows.
+type Area struct {
+	Sheet    string
+	FirstRow int
+	FirstCol int
+	LastRow  int
+	LastCol  int
+}
+
+// Contains returns true if the cell is in the area. Sheet names are compared case-insensitively.
+func (a Area) Contains(cell CellRef) bool {
+	return strings.EqualFold(a.Sheet, cell.Sheet) && a.FirstRow <= cell.Row && cell.Row <= a.LastRow && a.FirstCol <= cell.Col && cell.Col <= a.LastCol
+}
+
+func (a Area) size() int {
+	return (a.LastRow - a.FirstRow + 1) * (a.LastCol - a.FirstCol + 1)
+}
+
+func (a Area) String() string {
+	start := CellRef{Sheet: a.Sheet, Row: a.FirstRow, Col: a.FirstCol}
+	if a.FirstRow == a.LastRow && a.FirstCol == a.LastCol {
+		return start.String()
+	}
+	return start.String() + ":" + cellAddress{Row: a.LastRow, Col: a.LastCol}.String()
+}
+
+// FormulaEntry is a formula of a cell passed to BuildGraph. Cell is A1 style address like "B3".
+type FormulaEntry struct {
+	Sheet   string
+	Cell    string
+	Formula string
+}
+
+// Graph is a dependency graph of formulas in a workbook. Ranges are kept as areas,
+// so SUM(A1:A1048576) is one edge instead of a million.
+type Graph struct {
+	formulas map[CellRef]*graphFormula            // key is CellRef.key()
+	sheets   map[string]map[CellRef]*graphFormula // formulas per upper case sheet name
+	names    map[string][]graphReference          // references of defined names
+	order    []string                             // sheet order for 3D references
+
+	cellDependents map[CellRef][]*graphFormula // single cell precedents
+	areaDependents map[string][]areaDependent  // multi cell precedents per upper case sheet name
+	nameDependents map[string][]*graphFormula
+	nameCells      map[CellRef][]string  // names that refer the single cell
+	nameAreas      map[string][]nameArea // multi cell areas of names per upper case sheet name
+}
+
+type graphFormula struct {
+	cell       CellRef
+	node       *Node
+	references []graphReference
+	volatile   bool
+}
+
+// graphReference is a Range or Name token in a formula.
+type graphReference struct {
+	token *Token
+	area  Area   // referred area of Range token
+	name  string // upper case name of Name token
+}
+
+type areaDependent struct {
+	area    Area
+	formula *graphFormula
+}
+
+type nameArea struct {
+	area Area
+	name string
+}
+
+// NewGraph creates an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{
+		formulas:       make(map[CellRef]*graphFormula),
+		sheets:         make(map[string]map[CellRef]*graphFormula),
+		names:          make(map[string][]graphReference),
+		cellDependents: make(map[CellRef][]*graphFormula),
+		areaDependents: make(map[string][]areaDependent),
+		nameDependents: make(map[string][]*graphFormula),
+		nameCells:      make(map[CellRef][]string),
+		nameAreas:      make(map[string][]nameArea),
+	}
+}
+
+// BuildGraph creates Graph from formulas of a workbook.
+func BuildGraph(entries []FormulaEntry) (*Graph, error) {
+	g := NewGraph()
+	for _, entry := range entries {
+		if err := g.SetFormula(entry.Sheet, entry.Cell, entry.Formula); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// SetSheetOrder sets the order of sheets. It is needed to expand 3D references like Sheet1:Sheet3!A1.
+// Without it, only the first and the last sheets are referred.
+func (g *Graph) SetSheetOrder(sheets []string) {
+	g.order = append([]string(nil), sheets...)
+	for _, formula := range g.formulas {
+		g.unlink(formula)
+		g.link(formula)
+	}
+	g.indexNames()
+}
+
+// DefineName registers a defined name like "TaxRate" that refers formula like "Sheet1!$B$1".
+// References in the formula should have sheet names. The formula can refer other names.
+func (g *Graph) DefineName(name, formula string) error {
+	node, err := ParseWithOptions(strings.TrimPrefix(formula, "="), ParseOptions{})
+	if err != nil {
+		return err
+	}
+	references, _ := g.references(node, "")
+	g.names[strings.ToUpper(name)] = references
+	g.indexNames()
+	return nil
+}
+
+// referringNames returns the name and the names that refer it directly or indirectly.
+func (g *Graph) referringNames(name string) []string {
+	result := []string{strings.ToUpper(name)}
+	found := map[string]bool{result[0]: true}
+	for i := 0; i < len(result); i++ {
+		for other, references := range g.names {
+			if found[other] {
+				continue
+			}
+			for _, reference := range references {
+				if reference.name == result[i] {
+					found[other] = true
+					result = append(result, other)
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+// indexNames indexes the areas of defined names. Names that refer other names are expanded,
+// so all names are indexed again when a name or the sheet order is changed.
+func (g *Graph) indexNames() {
+	g.nameCells = make(map[CellRef][]string)
+	g.nameAreas = make(map[string][]nameArea)
+	for name := range g.names {
+		for _, area := range g.referenceAreas(graphReference{name: name}, nil) {
+			if area.size() == 1 {
+				key := CellRef{Sheet: area.Sheet, Row: area.FirstRow, Col: area.FirstCol}.key()
+				g.nameCells[key] = append(g.nameCells[key], name)
+			} else {
+				sheet := strings.ToUpper(area.Sheet)
+				g.nameAreas[sheet] = append(g.nameAreas[sheet], nameArea{area: area, name: name})
+			}
+		}
+	}
+}
+
+// SetFormula adds or replaces the formula of the cell. formula can start with "=".
+func (g *Graph) SetFormula(sheet, cell, formula string) error {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		return err
+	}
+	node, err := ParseWithOptions(strings.TrimPrefix(formula, "="), ParseOptions{})
+	if err != nil {
+		return fmt.Errorf("%s: %v", ref.String(), err)
+	}
+	g.remove(ref)
+	references, volatile := g.references(node, sheet)
+	entry := &graphFormula{
+		cell:       ref,
+		node:       node,
+		references: references,
+		volatile:   volatile,
+	}
+	key := ref.key()
+	g.formulas[key] = entry
+	if g.sheets[key.Sheet] == nil {
+		g.sheets[key.Sheet] = make(map[CellRef]*graphFormula)
+	}
+	g.sheets[key.Sheet][key] = entry
+	g.link(entry)
+	return nil
+}
+
+// RemoveFormula removes the formula of the cell.
+func (g *Graph) RemoveFormula(sheet, cell string) error {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		return err
+	}
+	g.remove(ref)
+	return nil
+}
+
+func (g *Graph) remove(ref CellRef) {
+	key := ref.key()
+	formula, ok := g.formulas[key]
+	if !ok {
+		return
+	}
+	g.unlink(formula)
+	delete(g.formulas, key)
+	delete(g.sheets[key.Sheet], key)
+}
+
+func (g *Graph) link(formula *graphFormula) {
+	for _, reference := range formula.references {
+		if reference.name != "" {
+			g.nameDependents[reference.name] = append(g.nameDependents[reference.name], formula)
+			continue
+		}
+		for _, area := range g.expand([]Area{reference.area}) {
+			if area.size() == 1 {
+				key := CellRef{Sheet: area.Sheet, Row: area.FirstRow, Col: area.FirstCol}.key()
+				g.cellDependents[key] = append(g.cellDependents[key], formula)
+			} else {
+				sheet := strings.ToUpper(area.Sheet)
+				g.areaDependents[sheet] = append(g.areaDependents[sheet], areaDependent{area: area, formula: formula})
+			}
+		}
+	}
+}
+
+func (g *Graph) unlink(formula *graphFormula) {
+	without := func(formulas []*graphFormula) []*graphFormula {
+		var result []*graphFormula
+		for _, f := range formulas {
+			if f != formula {
+				result = append(result, f)
+			}
+		}
+		return result
+	}
+	for _, reference := range formula.references {
+		if reference.name != "" {
+			g.nameDependents[reference.name] = without(g.nameDependents[reference.name])
+			continue
+		}
+		for _, area := range g.expand([]Area{reference.area}) {
+			if area.size() == 1 {
+				key := CellRef{Sheet: area.Sheet, Row: area.FirstRow, Col: area.FirstCol}.key()
+				if g.cellDependents[key] = without(g.cellDependents[key]); len(g.cellDependents[key]) == 0 {
+					delete(g.cellDependents, key)
+				}
+			} else {
+				sheet := strings.ToUpper(area.Sheet)
+				var result []areaDependent
+				for _, dependent := range g.areaDependents[sheet] {
+					if dependent.formula != formula {
+						result = append(result, dependent)
+					}
+				}
+				g.areaDependents[sheet] = result
+			}
+		}
+	}
+}
+
+// references extracts Range and Name tokens that node refers. 3D references are kept with the
+// last sheet in Area.Sheet joined by ":" and expanded by expand().
+func (g *Graph) references(node *Node, sheet string) ([]graphReference, bool) {
+	var references []graphReference
+	volatile := false
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		switch node.Type {
+		case SingleToken:
+			token := node.Token
+			switch token.Type {
+			case Range:
+				area, ok := tokenArea(token, sheet)
+				if ok && area.Sheet != "" {
+					references = append(references, graphReference{token: token, area: area})
+				}
+			case Name:
+				if token.Workbook == "" {
+					references = append(references, graphReference{token: token, name: strings.ToUpper(token.Text)})
+				}
+			}
+		case Function:
+			if IsVolatileFunction(node.Token.Text) {
+				volatile = true
+			}
+		case Intersection, RangeOp:
+			// only the cells in both areas (or between them) are referred
+			if area, ok := operatorArea(node, sheet); ok {
+				if area.Sheet != "" && area.FirstRow > 0 {
+					references = append(references, graphReference{token: node.Children[0].Token, area: area})
+				}
+				return
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return references, volatile
+}
+
+// operatorArea returns the area of Intersection or RangeOp of two Range tokens. FirstRow is 0 if
+// the intersection is empty. It returns false if the operands are not simple ranges on the same sheet.
+func operatorArea(node *Node, sheet string) (Area, bool) {
+	left, right := node.Children[0], node.Children[1]
+	if left.Type != SingleToken || right.Type != SingleToken || left.Token.Type != Range || right.Token.Type != Range {
+		return Area{}, false
+	}
+	a, ok := tokenArea(left.Token, sheet)
+	if !ok {
+		return Area{}, false
+	}
+	b, ok := tokenArea(right.Token, sheet)
+	if !ok || !strings.EqualFold(a.Sheet, b.Sheet) || strings.Contains(a.Sheet, ":") {
+		return Area{}, false
+	}
+	if node.Type == RangeOp {
+		return Area{
+			Sheet:    a.Sheet,
+			FirstRow: minInt(a.FirstRow, b.FirstRow),
+			FirstCol: minInt(a.FirstCol, b.FirstCol),
+			LastRow:  maxInt(a.LastRow, b.LastRow),
+			LastCol:  maxInt(a.LastCol, b.LastCol),
+		}, true
+	}
+	a.FirstRow, a.FirstCol = maxInt(a.FirstRow, b.FirstRow), maxInt(a.FirstCol, b.FirstCol)
+	a.LastRow, a.LastCol = minInt(a.LastRow, b.LastRow), minInt(a.LastCol, b.LastCol)
+	if a.FirstRow > a.LastRow || a.FirstCol > a.LastCol {
+		a.FirstRow = 0
+	}
+	return a, true
+}
+
+// tokenArea converts Range token into Area. 3D reference has "Sheet1:Sheet3" in Area.Sheet.
+func tokenArea(token *Token, sheet string) (Area, bool) {
+	start, end, ok := parseArea(token.Text)
+	if !ok || token.Workbook != "" {
+		// other workbooks are out of the graph
+		return Area{}, false
+	}
+	area := Area{Sheet: sheet, FirstRow: start.Row, FirstCol: start.Col, LastRow: end.Row, LastCol: end.Col}
+	if token.Sheet != "" {
+		area.Sheet = token.Sheet
+		if token.LastSheet != "" {
+			area.Sheet += ":" + token.LastSheet
+		}
+	}
+	return area, true
+}
+
+// expand expands 3D references into areas of each sheet.
+func (g *Graph) expand(areas []Area) []Area {
+	var result []Area
+	for _, area := range areas {
+		sheets := strings.Split(area.Sheet, ":")
+		if len(sheets) == 1 {
+			result = append(result, area)
+			continue
+		}
+		between := sheets
+		first, last := -1, -1
+		for i, sheet := range g.order {
+			if strings.EqualFold(sheet, sheets[0]) {
+				first = i
+			}
+			if strings.EqualFold(sheet, sheets[1]) {
+				last = i
+			}
+		}
+		if first >= 0 && last >= 0 {
+			if first > last {
+				first, last = last, first
+			}
+			between = g.order[first : last+1]
+		}
+		for _, sheet := range between {
+			expanded := area
+			expanded.Sheet = sheet
+			result = append(result, expanded)
+		}
+	}
+	return result
+}
+
+// Precedents returns areas that the formula of the cell refers directly. Defined names are expanded.
+// It returns nil if the cell has no formula.
+func (g *Graph) Precedents(cell CellRef) []Area {
+	formula, ok := g.formulas[cell.key()]
+	if !ok {
+		return nil
+	}
+	return g.precedentAreas(formula)
+}
+
+func (g *Graph) precedentAreas(formula *graphFormula) []Area {
+	var areas []Area
+	for _, reference := range formula.references {
+		areas = append(areas, g.referenceAreas(reference, nil)...)
+	}
+	return areas
+}
+
+// referenceAreas returns areas of the reference. Defined names are expanded recursively.
+func (g *Graph) referenceAreas(reference graphReference, visited map[string]bool) []Area {
+	if reference.name == "" {
+		return g.expand([]Area{reference.area})
+	}
+	if visited == nil {
+		visited = make(map[string]bool)
+	}
+	if visited[reference.name] {
+		return nil
+	}
+	visited[reference.name] = true
+	var areas []Area
+	for _, child := range g.names[reference.name] {
+		areas = append(areas, g.referenceAreas(child, visited)...)
+	}
+	return areas
+}
+
+// Dependents returns formula cells that refer the cell directly.
+func (g *Graph) Dependents(cell CellRef) []CellRef {
+	result := g.dependents(cell)
+	sortFormulas(result)
+	return cellRefs(result)
+}
+
+// AllDependents returns formula cells that are affected by the change of the cell directly or indirectly.
+func (g *Graph) AllDependents(cell CellRef) []CellRef {
+	visited := g.allDependents([]CellRef{cell})
+	result := make([]*graphFormula, 0, len(visited))
+	for formula := range visited {
+		result = append(result, formula)
+	}
+	sortFormulas(result)
+	return cellRefs(result)
+}
+
+// allDependents returns formulas affected by the cells directly or indirectly.
+func (g *Graph) allDependents(cells []CellRef) map[*graphFormula]bool {
+	visited := make(map[*graphFormula]bool)
+	queue := append([]CellRef(nil), cells...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, formula := range g.dependents(current) {
+			if !visited[formula] {
+				visited[formula] = true
+				queue = append(queue, formula.cell)
+			}
+		}
+	}
+	return visited
+}
+
+func (g *Graph) dependents(cell CellRef) []*graphFormula {
+	key := cell.key()
+	found := make(map[*graphFormula]bool)
+	var result []*graphFormula
+	add := func(formula *graphFormula) {
+		if !found[formula] {
+			found[formula] = true
+			result = append(result, formula)
+		}
+	}
+	for _, formula := range g.cellDependents[key] {
+		add(formula)
+	}
+	for _, dependent := range g.areaDependents[key.Sheet] {
+		if dependent.area.Contains(cell) {
+			add(dependent.formula)
+		}
+	}
+	for _, name := range g.nameCells[key] {
+		for _, formula := range g.nameDependents[name] {
+			add(formula)
+		}
+	}
+	for _, named := range g.nameAreas[key.Sheet] {
+		if named.area.Contains(cell) {
+			for _, formula := range g.nameDependents[named.name] {
+				add(formula)
+			}
+		}
+	}
+	return result
+}
+
+// formulasIn returns formula cells in the area. It checks cells in the area or formulas
+// on the sheet whichever is fewer.
+func (g *Graph) formulasIn(area Area) []*graphFormula {
+	sheet := g.sheets[strings.ToUpper(area.Sheet)]
+	var result []*graphFormula
+	if area.size() < len(sheet) {
+		for row := area.FirstRow; row <= area.LastRow; row++ {
+			for col := area.FirstCol; col <= area.LastCol; col++ {
+				if formula, ok := sheet[CellRef{Sheet: strings.ToUpper(area.Sheet), Row: row, Col: col}]; ok {
+					result = append(result, formula)
+				}
+			}
+		}
+		return result
+	}
+	for _, formula := range sheet {
+		if area.Contains(formula.cell) {
+			result = append(result, formula)
+		}
+	}
+	return result
+}
+
+// RecalculationOrder returns formula cells in the order that precedents are calculated before dependents.
+// It returns *CircularReferenceError if formulas have circular references.
+func (g *Graph) RecalculationOrder() ([]CellRef, error) {
+	formulas := make([]*graphFormula, 0, len(g.formulas))
+	for _, formula := range g.formulas {
+		formulas = append(formulas, formula)
+	}
+	order, rest := g.topologicalSort(formulas)
+	if len(rest) > 0 {
+		return cellRefs(order), &CircularReferenceError{References: g.CircularReferences()}
+	}
+	return cellRefs(order), nil
+}
+
+// topologicalSort sorts formulas. Formulas in cycles (and their dependents) are returned as rest.
+func (g *Graph) topologicalSort(formulas []*graphFormula) ([]*graphFormula, []*graphFormula) {
+	sortFormulas(formulas)
+	targets := make(map[*graphFormula]bool, len(formulas))
+	for _, formula := range formulas {
+		targets[formula] = true
+	}
+	inDegree := make(map[*graphFormula]int, len(formulas))
+	dependents := make(map[*graphFormula][]*graphFormula)
+	for _, formula := range formulas {
+		for _, edge := range g.precedentEdges(formula, targets) {
+			inDegree[formula]++
+			dependents[edge.to] = append(dependents[edge.to], formula)
+		}
+	}
+	var queue, order []*graphFormula
+	for _, formula := range formulas {
+		if inDegree[formula] == 0 {
+			queue = append(queue, formula)
+		}
+	}
+	for len(queue) > 0 {
+		formula := queue[0]
+		queue = queue[1:]
+		order = append(order, formula)
+		for _, dependent := range dependents[formula] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	var rest []*graphFormula
+	for _, formula := range formulas {
+		if inDegree[formula] > 0 {
+			rest = append(rest, formula)
+		}
+	}
+	return order, rest
+}
+
+// graphEdge is a reference from a formula to another formula made by token.
+type graphEdge struct {
+	to    *graphFormula
+	token *Token
+}
+
+// precedentEdges returns formulas in targets that formula refers. Each precedent appears once with the first token.
+func (g *Graph) precedentEdges(formula *graphFormula, targets map[*graphFormula]bool) []graphEdge {
+	var edges []graphEdge
+	seen := make(map[*graphFormula]bool)
+	for _, reference := range formula.references {
+		for _, area := range g.referenceAreas(reference, nil) {
+			precedents := g.formulasIn(area)
+			sortFormulas(precedents)
+			for _, precedent := range precedents {
+				if targets[precedent] && !seen[precedent] {
+					seen[precedent] = true
+					edges = append(edges, graphEdge{to: precedent, token: reference.token})
+				}
+			}
+		}
+	}
+	return edges
+}
+
+func sortFormulas(formulas []*graphFormula) {
+	sort.Slice(formulas, func(i, j int) bool {
+		a, b := formulas[i].cell.key(), formulas[j].cell.key()
+		if a.Sheet != b.Sheet {
+			return a.Sheet < b.Sheet
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+}
+
+func cellRefs(formulas []*graphFormula) []CellRef {
+	result := make([]CellRef, len(formulas))
+	for i, formula := range formulas {
+		result[i] = formula.cell
+	}
+	return result
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,149 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"testing"
+)
+
+func cellRefsString(refs []CellRef) string {
+	return fmt.Sprint(refs)
+}
+
+func mustCellRef(t *testing.T, sheet, cell string) CellRef {
+	ref, err := ParseCellRef(sheet, cell)
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	return ref
+}
+
+func TestGraphPrecedentsAndDependents(t *testing.T) {
+	g, err := BuildGraph([]FormulaEntry{
+		{Sheet: "Sheet1", Cell: "B1", Formula: "=A1*2"},
+		{Sheet: "Sheet1", Cell: "B2", Formula: "SUM(A1:A10)+Data!C3"},
+		{Sheet: "Sheet1", Cell: "B3", Formula: "B1+B2+TaxRate"},
+		{Sheet: "Data", Cell: "D1", Formula: "'Sheet1'!B3"},
+	})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if err := g.DefineName("TaxRate", "Data!$A$1"); err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if precedents := fmt.Sprint(g.Precedents(mustCellRef(t, "Sheet1", "B2"))); precedents != "[Sheet1!A1:A10 Data!C3]" {
+		t.Errorf("precedents of B2 are wrong: %s", precedents)
+	}
+	if precedents := fmt.Sprint(g.Precedents(mustCellRef(t, "Sheet1", "B3"))); precedents != "[Sheet1!B1 Sheet1!B2 Data!A1]" {
+		t.Errorf("precedents of B3 are wrong: %s", precedents)
+	}
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Sheet1", "A1"))); dependents != "[Sheet1!B1 Sheet1!B2]" {
+		t.Errorf("dependents of A1 are wrong: %s", dependents)
+	}
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "data", "A1"))); dependents != "[Sheet1!B3]" {
+		t.Errorf("dependents of Data!A1 are wrong: %s", dependents)
+	}
+	if dependents := cellRefsString(g.AllDependents(mustCellRef(t, "Sheet1", "A5"))); dependents != "[Data!D1 Sheet1!B2 Sheet1!B3]" {
+		t.Errorf("all dependents of A5 are wrong: %s", dependents)
+	}
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Sheet1", "A11"))); dependents != "[]" {
+		t.Errorf("A11 should not have dependents: %s", dependents)
+	}
+	order, err := g.RecalculationOrder()
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if cellRefsString(order) != "[Sheet1!B1 Sheet1!B2 Sheet1!B3 Data!D1]" {
+		t.Errorf("recalculation order is wrong: %s", cellRefsString(order))
+	}
+}
+
+func TestGraphReplaceAndRemoveFormula(t *testing.T) {
+	g := NewGraph()
+	g.SetFormula("Sheet1", "B1", "A1")
+	g.SetFormula("Sheet1", "B1", "A2")
+	if dependents := g.Dependents(mustCellRef(t, "Sheet1", "A1")); len(dependents) != 0 {
+		t.Errorf("replaced formula should not depend on A1: %v", dependents)
+	}
+	if dependents := g.Dependents(mustCellRef(t, "Sheet1", "A2")); len(dependents) != 1 {
+		t.Errorf("replaced formula should depend on A2: %v", dependents)
+	}
+	g.RemoveFormula("Sheet1", "B1")
+	if dependents := g.Dependents(mustCellRef(t, "Sheet1", "A2")); len(dependents) != 0 {
+		t.Errorf("removed formula should not have precedents: %v", dependents)
+	}
+}
+
+func TestGraphLargeRange(t *testing.T) {
+	g := NewGraph()
+	for row := 1; row <= 1000; row++ {
+		g.SetFormula("Sheet1", fmt.Sprintf("A%d", row), fmt.Sprintf("C%d*2", row))
+	}
+	g.SetFormula("Sheet1", "B1", "SUM(A1:A1048576)")
+	g.SetFormula("Sheet1", "B2", "B1+1")
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Sheet1", "A500000"))); dependents != "[Sheet1!B1]" {
+		t.Errorf("dependents of A500000 are wrong: %s", dependents)
+	}
+	order, err := g.RecalculationOrder()
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if len(order) != 1002 || order[1000].String() != "Sheet1!B1" || order[1001].String() != "Sheet1!B2" {
+		t.Errorf("B1 and B2 should be calculated after A column: %v", order[len(order)-2:])
+	}
+}
+
+func TestGraph3DReference(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{{Sheet: "Total", Cell: "A1", Formula: "SUM(Jan:Mar!B2)"}})
+	if dependents := g.Dependents(mustCellRef(t, "Feb", "B2")); len(dependents) != 0 {
+		t.Errorf("sheets between should be unknown without sheet order")
+	}
+	g.SetSheetOrder([]string{"Total", "Jan", "Feb", "Mar"})
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Feb", "B2"))); dependents != "[Total!A1]" {
+		t.Errorf("dependents of Feb!B2 are wrong: %s", dependents)
+	}
+}
+
+func TestGraphNameDependents(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{{Sheet: "Sheet1", Cell: "A1", Formula: "SUM(Rate, Months)"}})
+	g.DefineName("Rate", "Other")
+	g.DefineName("Other", "Data!$B$1:$B$5")
+	g.DefineName("Months", "Jan:Mar!A1")
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Data", "B3"))); dependents != "[Sheet1!A1]" {
+		t.Errorf("dependents of Data!B3 are wrong: %s", dependents)
+	}
+	g.DefineName("Other", "Data!$C$1")
+	if dependents := g.Dependents(mustCellRef(t, "Data", "B3")); len(dependents) != 0 {
+		t.Errorf("Data!B3 should not have dependents after redefinition: %v", dependents)
+	}
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Data", "C1"))); dependents != "[Sheet1!A1]" {
+		t.Errorf("dependents of Data!C1 are wrong: %s", dependents)
+	}
+	g.SetSheetOrder([]string{"Jan", "Feb", "Mar"})
+	if dependents := cellRefsString(g.Dependents(mustCellRef(t, "Feb", "A1"))); dependents != "[Sheet1!A1]" {
+		t.Errorf("dependents of Feb!A1 are wrong: %s", dependents)
+	}
+}
+
+func TestGraphCircularReference(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{
+		{Sheet: "Sheet1", Cell: "A1", Formula: "B1+1"},
+		{Sheet: "Sheet1", Cell: "B1", Formula: "A1+1"},
+	})
+	if _, err := g.RecalculationOrder(); err == nil {
+		t.Errorf("circular reference should be error")
+	}
+}
+
+func TestGraphIntersection(t *testing.T) {
+	g, _ := BuildGraph([]FormulaEntry{
+		{Sheet: "Sheet1", Cell: "F1", Formula: "SUM(A1:C3 B2:D4)"},
+		{Sheet: "Sheet1", Cell: "F2", Formula: "SUM((A1,E5))"},
+	})
+	f1, _ := ParseCellRef("Sheet1", "F1")
+	precedents := g.Precedents(f1)
+	if len(precedents) != 1 || precedents[0].String() != "Sheet1!B2:C3" {
+		t.Errorf("precedents should be only the intersection, but %v", precedents)
+	}
+	a1, _ := ParseCellRef("Sheet1", "A1")
+	if dependents := g.Dependents(a1); len(dependents) != 1 || dependents[0].String() != "Sheet1!F2" {
+		t.Errorf("A1 should be referred only by F2, but %v", dependents)
+	}
+}
@@ -4,21 +4,28 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 type TokenType int
 
 const (
-	Number     TokenType = iota // number
-	String                      // double quoted string
-	Bool                        // TRUE/FALSE
-	Operator                    // +, -, *, /, ^, &
-	LParen                      // (
-	RParen                      // )
-	Comma                       // ,
-	Comparator                  // =, <>, <, >, <=, >=
-	Name                        // function name, named range etc
-	Range                       // A2:B3
+	Number        TokenType = iota // number
+	String                         // double quoted string
+	Bool                           // TRUE/FALSE
+	Operator                       // +, -, *, /, ^, &
+	LParen                         // (
+	RParen                         // )
+	Comma                          // ,
+	Comparator                     // =, <>, <, >, <=, >=
+	Name                           // function name, named range etc
+	Range                          // A2:B3
+	Error                          // #N/A, #REF! etc
+	LBrace                         // { of array constant
+	RBrace                         // }
+	Semicolon                      // ; row separator of array constant
+	StructuredRef                  // Table1[Column]
 	Null
 )
 
@@ -44,35 +51,84 @@ func (tt TokenType) String() string {
 		return "Name"
 	case Range:
 		return "Range"
+	case Error:
+		return "Error"
+	case LBrace:
+		return "LBrace"
+	case RBrace:
+		return "RBrace"
+	case Semicolon:
+		return "Semicolon"
+	case StructuredRef:
+		return "StructuredRef"
 	}
 	return "Unknown"
 }
 
 type Token struct {
-	Type TokenType
-	Text string
-	Line int
-	Col  int
+	Type       TokenType
+	Text       string
+	Workbook   string               // external workbook of [Book1]Sheet1!A1 (without brackets). Path is kept like C:\Data\Book1.xlsx
+	Sheet      string               // sheet name of Sheet1!A1 (without quotes). Empty if the reference has no sheet
+	LastSheet  string               // last sheet name of 3D reference like Sheet1:Sheet3!A1
+	Structured *StructuredReference // parsed table reference of StructuredRef token
+	Line       int
+	Col        int
+	Leading    string // spaces and line breaks before the token (TokenizeOptions.Trivia)
+	Trailing   string // spaces and line breaks after the last token (TokenizeOptions.Trivia)
+	source     *tokenSource
 }
 
-var rangePattern *regexp.Regexp = regexp.MustCompile(`^(\$?[A-Z]+\$?[1-9][0-9]*)(:(\$?[A-Z]+|\$?[1-9][0-9]*|\$?[A-Z]+\$?[1-9][0-9]*))?$`)
+// tokenSource keeps the original text of the token to write it back as it was.
+type tokenSource struct {
+	raw       string // text in the formula like "'Sheet1'!A1"
+	formatted string // formatToken() result when it was tokenized to detect edits
+}
+
+// exponentPattern matches the head of a number in scientific notation like "1.5E" of "1.5E+10".
+var exponentPattern *regexp.Regexp = regexp.MustCompile(`^([0-9]+\.?[0-9]*|\.[0-9]+)[Ee]$`)
+
+// rangePattern matches A1 style references: cells and areas (A1, $A$1:B3), whole columns (A:A, $B:$D) and whole rows (1:1, $3:$5).
+// Column names are case-insensitive like Excel (a1:b2).
+var rangePattern *regexp.Regexp = regexp.MustCompile(`(?i)^(\$?[A-Z]{1,3}\$?[1-9][0-9]*)(:(\$?[A-Z]{1,3}|\$?[1-9][0-9]*|\$?[A-Z]{1,3}\$?[1-9][0-9]*))?$|^\$?[A-Z]{1,3}:\$?[A-Z]{1,3}$|^\$?[1-9][0-9]*:\$?[1-9][0-9]*$`)
+
+// isA1Range returns true if text matches rangePattern and is in the grid (A1:XFD1048576).
+// Out of the grid like ZZZ1 or A1048577 is a name like Excel.
+func isA1Range(text string) bool {
+	if !rangePattern.MatchString(text) {
+		return false
+	}
+	for _, part := range strings.Split(strings.ToUpper(text), ":") {
+		if _, ok := parseReferencePart(part); !ok {
+			return false
+		}
+	}
+	return true
+}
 
 var symbolSeparator map[rune]bool = map[rune]bool{
-	' ':  true,
-	'+':  true,
-	'-':  true,
-	'*':  true,
-	'/':  true,
-	'^':  true,
-	'&':  true,
-	'(':  true,
-	')':  true,
-	',':  true,
-	'<':  true,
-	'>':  true,
-	'=':  true,
-	'\r': true,
-	'\n': true,
+	'%':      true,
+	' ':      true,
+	'+':      true,
+	'-':      true,
+	'*':      true,
+	'/':      true,
+	'^':      true,
+	'&':      true,
+	'(':      true,
+	')':      true,
+	',':      true,
+	'{':      true,
+	'}':      true,
+	';':      true,
+	'[':      true,
+	'<':      true,
+	'>':      true,
+	'=':      true,
+	'\r':     true,
+	'\n':     true,
+	'\t':     true,
+	'\u00a0': true,
 }
 
 var singleCharNode map[rune]TokenType = map[rune]TokenType{
@@ -82,37 +138,146 @@ var singleCharNode map[rune]TokenType = map[rune]TokenType{
 	'/': Operator,
 	'^': Operator,
 	'&': Operator,
+	'%': Operator,
 	'(': LParen,
 	')': RParen,
 	'=': Comparator,
 	',': Comma,
+	'{': LBrace,
+	'}': RBrace,
+	';': Semicolon,
+}
+
+// TokenizeOptions controls the notation that Tokenize accepts.
+type TokenizeOptions struct {
+	// R1C1 reads references in R1C1 notation like R[-1]C2 instead of A1 notation.
+	R1C1 bool
+	// Trivia keeps spaces and line breaks in Token.Leading and Token.Trailing and the original
+	// text of tokens, so FormatTokens can reproduce the formula as it was.
+	Trivia bool
+	// Separators are the argument, decimal and array separators of the formula. The zero value is
+	// the invariant (en-US) ones. Tokens always have the invariant text like "," and "0.5".
+	Separators Separators
 }
 
 func Tokenize(formula string) ([]*Token, error) {
+	return TokenizeWithOptions(formula, TokenizeOptions{})
+}
+
+// TokenizeWithOptions is same as Tokenize, but it accepts R1C1 notation if options.R1C1 is true.
+func TokenizeWithOptions(formula string, options TokenizeOptions) ([]*Token, error) {
 	tokens := []*Token{}
+	isRange := isA1Range
+	if options.R1C1 {
+		isRange = r1c1Pattern.MatchString
+	}
+	separators := options.Separators.normalize()
+	if err := separators.validate(); err != nil {
+		return nil, err
+	}
+	// words end at the argument separator outside of array constants and at the array separators inside
+	outside := wordSyntax{r1c1: options.R1C1, separators: symbolSeparator, decimal: separators.Decimal}
+	inside := outside
+	if separators != InvariantSeparators {
+		outside.separators = withSeparators(separators.Argument)
+		inside.separators = withSeparators(separators.ArrayColumn, separators.ArrayRow)
+	}
+	depth := 0
 
 	source := []rune(formula)
 	index := 0
 	line := 1
 	lineHead := 0
+	start, count, triviaHead := 0, 0, 0
+	gapHead, gapLine, gapCol := -1, 0, 0
+	// attach handles the token read at the last loop. Spaces between references become the
+	// intersection operator. It also stores trivia and the original text of the token.
+	attach := func() {
+		if len(tokens) == count {
+			return
+		}
+		if gapHead >= 0 && count > 0 && endsReference(tokens[count-1]) && startsReference(tokens[count-1], tokens[count]) {
+			intersection := &Token{Type: Operator, Text: " ", Line: gapLine, Col: gapCol}
+			tokens = append(tokens[:count], append([]*Token{intersection}, tokens[count:]...)...)
+			if options.Trivia {
+				intersection.source = &tokenSource{raw: string(source[gapHead:start]), formatted: " "}
+				triviaHead = start
+			}
+			count++
+		}
+		gapHead = -1
+		if !options.Trivia {
+			return
+		}
+		token := tokens[count]
+		token.Leading = string(source[triviaHead:start])
+		token.source = &tokenSource{raw: string(source[start:index]), formatted: formatToken(token)}
+		triviaHead = index
+	}
 	for index < len(source) {
+		attach()
+		start, count = index, len(tokens)
 		ch := source[index]
-		if nodeType, ok := singleCharNode[ch]; ok {
+		workbook := ""
+		if ch == '[' {
+			// [Book1]Sheet1!A1 or [1]!Name refers other workbook
+			if book, next, ok := readWorkbook(source, index); ok {
+				workbook, index, ch = book, next, source[next]
+			}
+		}
+		// ,5 is 0.5 with the decimal comma
+		number := ch == separators.Decimal && index+1 < len(source) && isDigit(source[index+1])
+		if number {
+			// read as a word
+		} else if depth == 0 && ch == separators.Argument || depth > 0 && (ch == separators.ArrayColumn || ch == separators.ArrayRow) {
+			nodeType, text := Comma, ","
+			if depth > 0 && ch == separators.ArrayRow {
+				nodeType, text = Semicolon, ";"
+			}
+			tokens = append(tokens, &Token{
+				Type: nodeType,
+				Text: text,
+				Line: line,
+				Col:  index - lineHead + 1,
+			})
+			index++
+			continue
+		}
+		if (ch == ',' || ch == ';') && separators != InvariantSeparators && !number {
+			return tokens, fmt.Errorf(`unexpected character at %d:%d: %s`, line, index-lineHead+1, string(ch))
+		}
+		if nodeType, ok := singleCharNode[ch]; ok && !number {
 			tokens = append(tokens, &Token{
 				Type: nodeType,
 				Text: string(ch),
 				Line: line,
 				Col:  index - lineHead + 1,
 			})
+			if nodeType == LBrace {
+				depth++
+			} else if nodeType == RBrace && depth > 0 {
+				depth--
+			}
 			index++
 			continue
 		}
+		syntax := outside
+		if depth > 0 {
+			syntax = inside
+		}
 		switch ch {
-		case ' ':
+		case ' ', '\t', '\u00a0':
+			if gapHead < 0 {
+				gapHead, gapLine, gapCol = index, line, index-lineHead+1
+			}
 			index++
 			continue
-		case '\r':
-			if index+1 < len(source) && source[index+1] == '\n' {
+		case '\r', '\n':
+			if gapHead < 0 {
+				gapHead, gapLine, gapCol = index, line, index-lineHead+1
+			}
+			// CR, LF (Alt+Enter) and CRLF
+			if ch == '\r' && index+1 < len(source) && source[index+1] == '\n' {
 				index += 2
 			} else {
 				index++
@@ -120,6 +285,15 @@ func Tokenize(formula string) ([]*Token, error) {
 			line++
 			lineHead = index
 			continue
+		case ':':
+			// range operator between references like A1:INDEX(B:B,5). Literal ranges like A1:B3 are read as a word
+			tokens = append(tokens, &Token{
+				Type: Operator,
+				Text: ":",
+				Line: line,
+				Col:  index - lineHead + 1,
+			})
+			index++
 		case '<':
 			if index+1 < len(source) {
 				switch source[index+1] {
@@ -171,13 +345,20 @@ func Tokenize(formula string) ([]*Token, error) {
 			}
 			continue
 		case '"':
+			// "" in the string is an escaped double quotation
+			var text []rune
 			last := index + 1
 			found := false
 			for last < len(source) {
 				if source[last] == '"' {
+					if last+1 < len(source) && source[last+1] == '"' {
+						text = append(text, '"')
+						last += 2
+						continue
+					}
 					tokens = append(tokens, &Token{
 						Type: String,
-						Text: string(source[index+1 : last]),
+						Text: string(text),
 						Line: line,
 						Col:  index - lineHead + 1,
 					})
@@ -185,28 +366,134 @@ func Tokenize(formula string) ([]*Token, error) {
 					found = true
 					break
 				}
+				text = append(text, source[last])
 				last++
 			}
 			if !found {
 				return tokens, fmt.Errorf(`closing double quoatation is missing: %s`, string(source[index:]))
 			}
-		default:
-			start := index
-			last := index
+		case '#':
+			text := errorLiteral(source[index:])
+			if text == "" {
+				return tokens, fmt.Errorf(`unknown error literal at %d:%d: %s`, line, index-lineHead+1, string(source[index:]))
+			}
+			tokens = append(tokens, &Token{
+				Type: Error,
+				Text: text,
+				Line: line,
+				Col:  index - lineHead + 1,
+			})
+			index += len([]rune(text))
+		case '[':
+			// [@Amount] in the table
+			ref, next, err := readStructuredReference(source, index, "")
+			if err != nil {
+				return tokens, err
+			}
+			tokens = append(tokens, &Token{
+				Type:       StructuredRef,
+				Text:       string(source[index:next]),
+				Structured: ref,
+				Line:       line,
+				Col:        index - lineHead + 1,
+			})
+			index = next
+		case '\'':
+			// 'My Sheet'!A1
+			var sheet []rune
+			last := index + 1
 			found := false
-			var text string
 			for last < len(source) {
-				if symbolSeparator[source[last]] {
-					text = string(source[index:last])
-					index = last
+				if source[last] == '\'' {
+					if last+1 < len(source) && source[last+1] == '\'' {
+						sheet = append(sheet, '\'')
+						last += 2
+						continue
+					}
 					found = true
 					break
 				}
+				sheet = append(sheet, source[last])
 				last++
 			}
 			if !found {
-				text = string(source[index:])
-				index = len(source)
+				return tokens, fmt.Errorf(`closing single quoatation is missing: %s`, string(source[index:]))
+			}
+			if workbook == "" {
+				// '[Book 1]My Sheet'!A1 or 'C:\Data\[Book1.xlsx]Sheet1'!A1
+				workbook, sheet = splitWorkbook(sheet)
+			}
+			if len(sheet) == 0 {
+				return tokens, fmt.Errorf(`sheet name is empty at %d:%d`, line, start-lineHead+1)
+			}
+			if last+1 >= len(source) || source[last+1] != '!' {
+				return tokens, fmt.Errorf(`'!' is needed after sheet name at %d:%d`, line, start-lineHead+1)
+			}
+			var text string
+			text, index = readWord(source, last+2, syntax)
+			if colon := rangeOperatorIndex(text, isRange, source, index); colon >= 0 {
+				// 'My Sheet'!A1:INDEX(...)
+				text = text[:colon]
+				index = last + 2 + utf8.RuneCountInString(text)
+			}
+			token, err := referenceToken(text, string(sheet), isRange, source, index, line, start-lineHead+1)
+			if err != nil {
+				return tokens, err
+			}
+			token.Workbook = workbook
+			tokens = append(tokens, token)
+		default:
+			head := index
+			var text string
+			text, index = readWord(source, index, syntax)
+			if separators.Decimal != '.' && text != "" && (isDigit(rune(text[0])) || rune(text[0]) == separators.Decimal) {
+				// 0,5 is 0.5 with the decimal comma
+				if strings.ContainsRune(text, '.') {
+					return tokens, fmt.Errorf(`unexpected '.' in number at %d:%d: %s`, line, start-lineHead+1, text)
+				}
+				text = strings.Replace(text, string(separators.Decimal), ".", 1)
+			}
+			if exponentPattern.MatchString(text) && index+1 < len(source) && (source[index] == '+' || source[index] == '-') && isDigit(source[index+1]) {
+				// the sign of the exponent is a part of the number
+				exponent := index
+				index++
+				for index < len(source) && isDigit(source[index]) {
+					index++
+				}
+				text += string(source[exponent:index])
+			}
+			if text == "" {
+				return tokens, fmt.Errorf(`unexpected character at %d:%d: %s`, line, start-lineHead+1, string(ch))
+			}
+			if text[0] == '!' {
+				if workbook == "" {
+					return tokens, fmt.Errorf(`sheet name is missing at %d:%d`, line, start-lineHead+1)
+				}
+				// [1]!Name is a workbook level name
+				token, err := referenceToken(text[1:], "", isRange, source, index, line, start-lineHead+1)
+				if err != nil || token.Type != Name || token.Text == "" {
+					return tokens, fmt.Errorf(`name is missing after workbook at %d:%d`, line, start-lineHead+1)
+				}
+				token.Workbook = workbook
+				tokens = append(tokens, token)
+				continue
+			}
+			if quote := strings.Index(text, ":'"); quote >= 0 {
+				// Name1:'My Sheet'!A1
+				text = text[:quote]
+				index = head + utf8.RuneCountInString(text)
+			}
+			offset := 0
+			if sheetEnd := strings.IndexRune(text, '!'); sheetEnd > 0 {
+				// colon in the sheet part is a 3D reference like Sheet1:Sheet3!A1 except A1:Sheet1!B2
+				if colon := strings.IndexRune(text[:sheetEnd], ':'); colon < 0 || !isRange(text[:colon]) {
+					offset = sheetEnd + 1
+				}
+			}
+			if colon := rangeOperatorIndex(text[offset:], isRange, source, index); colon >= 0 {
+				// A1:INDEX(...), Name1:Name2, Sheet1!A1:Sheet1!B2
+				text = text[:offset+colon]
+				index = head + utf8.RuneCountInString(text)
 			}
 			if _, err := strconv.ParseFloat(text, 64); err == nil {
 				tokens = append(tokens, &Token{
@@ -215,14 +502,31 @@ func Tokenize(formula string) ([]*Token, error) {
 					Line: line,
 					Col:  start - lineHead + 1,
 				})
-			} else if rangePattern.MatchString(text) {
+			} else if index < len(source) && source[index] == '[' {
+				ref, next, err := readStructuredReference(source, index, text)
+				if err != nil {
+					return tokens, err
+				}
 				tokens = append(tokens, &Token{
-					Type: Range,
-					Text: text,
-					Line: line,
-					Col:  start - lineHead + 1,
+					Type:       StructuredRef,
+					Text:       string(source[start:next]),
+					Structured: ref,
+					Line:       line,
+					Col:        start - lineHead + 1,
 				})
-			} else if text == "TRUE" || text == "FALSE" {
+				index = next
+			} else if sheetEnd := strings.IndexRune(text, '!'); sheetEnd > 0 {
+				// Sheet1!A1, Sheet1:Sheet3!A1
+				token, err := referenceToken(text[sheetEnd+1:], text[:sheetEnd], isRange, source, index, line, start-lineHead+1)
+				if err != nil {
+					return tokens, err
+				}
+				token.Workbook = workbook
+				tokens = append(tokens, token)
+			} else if workbook != "" {
+				return tokens, fmt.Errorf(`'!' is needed after sheet name at %d:%d`, line, start-lineHead+1)
+			} else if (strings.EqualFold(text, "TRUE") || strings.EqualFold(text, "FALSE")) && (index >= len(source) || source[index] != '(') {
+				// true is TRUE, but TRUE() is a function
 				tokens = append(tokens, &Token{
 					Type: Bool,
 					Text: text,
@@ -230,15 +534,268 @@ func Tokenize(formula string) ([]*Token, error) {
 					Col:  start - lineHead + 1,
 				})
 			} else {
-				tokens = append(tokens, &Token{
-					Type: Name,
-					Text: text,
-					Line: line,
-					Col:  start - lineHead + 1,
-				})
+				token, _ := referenceToken(text, "", isRange, source, index, line, start-lineHead+1)
+				tokens = append(tokens, token)
 			}
 		}
 	}
+	attach()
+	if options.Trivia && len(tokens) > 0 {
+		tokens[len(tokens)-1].Trailing = string(source[triviaHead:])
+	}
 
 	return tokens, nil
 }
+
+// rangeOperatorIndex returns the byte index of ':' in the reference part of the word that should be
+// the range operator. It returns -1 if the word has no colon or it is a literal range like "A1:B3".
+// next is the index of the character after the word.
+func rangeOperatorIndex(word string, isRange func(string) bool, source []rune, next int) int {
+	colon := strings.IndexRune(word, ':')
+	if colon < 0 {
+		return -1
+	}
+	if isRange(word) && (next >= len(source) || source[next] != '(') {
+		return -1
+	}
+	// keep the literal range at the head of A1:B2:C3 to make it left associative
+	for i := len(word) - 1; i > colon; i-- {
+		if word[i] == ':' && isRange(word[:i]) {
+			return i
+		}
+	}
+	return colon
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isDigits(runes []rune) bool {
+	for _, r := range runes {
+		if !isDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// readWorkbook reads the workbook part like "[Book1]" of an external reference at index. It is a workbook only if
+// a sheet name like "Sheet1!" or "'My Sheet'!" or a workbook level name like "!Name" follows it.
+func readWorkbook(source []rune, index int) (string, int, bool) {
+	end := index + 1
+	for end < len(source) && source[end] != ']' {
+		if source[end] == '[' {
+			return "", index, false
+		}
+		end++
+	}
+	if end == index+1 || end+1 >= len(source) {
+		return "", index, false
+	}
+	if source[end+1] != '\'' {
+		if word, _ := readWord(source, end+1, wordSyntax{separators: symbolSeparator, decimal: '.'}); !strings.ContainsRune(word, '!') {
+			return "", index, false
+		}
+	}
+	return string(source[index+1 : end]), end + 1, true
+}
+
+// splitWorkbook splits a quoted sheet name like "C:\Data\[Book1.xlsx]Sheet1" into the workbook
+// "C:\Data\Book1.xlsx" and the sheet name.
+func splitWorkbook(sheet []rune) (string, []rune) {
+	text := string(sheet)
+	open := strings.IndexRune(text, '[')
+	end := strings.IndexRune(text, ']')
+	if open < 0 || end < open+2 {
+		return "", sheet
+	}
+	return text[:open] + text[open+1:end], []rune(text[end+1:])
+}
+
+// endsReference returns true if the token can be the left operand of the intersection operator.
+func endsReference(token *Token) bool {
+	return token.Type == Range || token.Type == Name || token.Type == StructuredRef || token.Type == RParen
+}
+
+// startsReference returns true if next can be the right operand of the intersection operator after previous.
+// "SUM (A1)" is not an intersection.
+func startsReference(previous, next *Token) bool {
+	switch next.Type {
+	case Range, Name, StructuredRef:
+		return true
+	case LParen:
+		return previous.Type != Name
+	}
+	return false
+}
+
+// errorLiteral returns the error literal like "#DIV/0!" at the head of the source.
+func errorLiteral(source []rune) string {
+	if len(source) > 16 {
+		source = source[:16]
+	}
+	head := string(source)
+	for text := range errorCodes {
+		if len(head) >= len(text) && strings.EqualFold(head[:len(text)], text) {
+			// #n/a is #N/A
+			return head[:len(text)]
+		}
+	}
+	return ""
+}
+
+// wordSyntax is how readWord splits words.
+type wordSyntax struct {
+	r1c1       bool          // brackets of R1C1 reference like R[-1]C[2] are parts of the word
+	separators map[rune]bool // characters that end the word
+	decimal    rune          // decimal separator in numbers like "0,5" is a part of the word
+}
+
+// withSeparators returns symbolSeparator with the separators of the locale.
+func withSeparators(runes ...rune) map[rune]bool {
+	separators := make(map[rune]bool, len(symbolSeparator)+len(runes))
+	for r := range symbolSeparator {
+		separators[r] = true
+	}
+	for _, r := range runes {
+		separators[r] = true
+	}
+	return separators
+}
+
+// readWord reads a name, a number or a reference until the next separator.
+func readWord(source []rune, index int, syntax wordSyntax) (string, int) {
+	last := index
+	for last < len(source) {
+		if source[last] == '[' && syntax.r1c1 && last > index && strings.ContainsRune("RCrc", source[last-1]) {
+			if end := r1c1Offset(source, last); end > 0 {
+				last = end
+				continue
+			}
+		}
+		if source[last] == syntax.decimal && last+1 < len(source) && isDigit(source[last+1]) && isDigits(source[index:last]) {
+			last++
+			continue
+		}
+		if syntax.separators[source[last]] {
+			break
+		}
+		last++
+	}
+	return string(source[index:last]), last
+}
+
+// referenceToken creates Range or Name token. Range is detected by isRange for A1 or R1C1 notation.
+// next is the index of the character after the text.
+func referenceToken(text, sheet string, isRange func(string) bool, source []rune, next, line, col int) (*Token, error) {
+	token := &Token{
+		Type: Name,
+		Text: text,
+		Line: line,
+		Col:  col,
+	}
+	if sheet != "" {
+		if text == "" || text[0] == '!' {
+			return nil, fmt.Errorf(`reference is missing after sheet name at %d:%d`, line, col)
+		}
+		// Sheet1:Sheet3!A1 refers same cells in the sheets
+		if i := strings.IndexRune(sheet, ':'); i > 0 && i < len(sheet)-1 {
+			token.Sheet, token.LastSheet = sheet[:i], sheet[i+1:]
+		} else {
+			token.Sheet = sheet
+		}
+	}
+	// Sheet1!#REF! remains after the referred sheet is deleted
+	if _, ok := errorCodes[strings.ToUpper(text)]; ok {
+		token.Type = Error
+		return token, nil
+	}
+	// LOG10( or DAYS360( looks like a cell address, but it is a function name
+	if isRange(text) && (next >= len(source) || source[next] != '(') {
+		token.Type = Range
+	}
+	return token, nil
+}
+
+// Normalized returns the upper case form of Text for case-insensitive tokens: booleans, errors,
+// references and names (function names and defined names). Other tokens return Text as it is.
+func (t *Token) Normalized() string {
+	switch t.Type {
+	case Bool, Error, Range, Name:
+		return strings.ToUpper(t.Text)
+	}
+	return t.Text
+}
+
+// Raw returns the text of the token as it was written in the formula like "'Sheet1'!A1" or
+// `"He said ""hi"""`. Without TokenizeOptions.Trivia, it is rebuilt from the token like Format.
+func (t *Token) Raw() string {
+	if t.source != nil {
+		return t.source.raw
+	}
+	return formatToken(t)
+}
+
+// SheetPrefix returns the sheet part of the reference like "Sheet1!" or "'My Sheet'!".
+// It returns empty string if the token has no sheet.
+func (t *Token) SheetPrefix() string {
+	if t.Workbook != "" {
+		return t.workbookPrefix()
+	}
+	if t.Sheet == "" {
+		return ""
+	}
+	if t.LastSheet == "" {
+		return quoteSheetName(t.Sheet) + "!"
+	}
+	if quoteSheetName(t.Sheet) != t.Sheet || quoteSheetName(t.LastSheet) != t.LastSheet {
+		return "'" + strings.Replace(t.Sheet+":"+t.LastSheet, "'", "''", -1) + "'!"
+	}
+	return t.Sheet + ":" + t.LastSheet + "!"
+}
+
+// workbookPrefix returns the prefix of an external reference like "[Book1]Sheet1!" or "'C:\Data\[Book1.xlsx]Sheet1'!".
+func (t *Token) workbookPrefix() string {
+	if t.Sheet == "" {
+		// [1]!Name can't be quoted
+		return "[" + t.Workbook + "]!"
+	}
+	directory, book := "", t.Workbook
+	if i := strings.LastIndexAny(book, `\/`); i >= 0 && i < len(book)-1 {
+		directory, book = book[:i+1], book[i+1:]
+	}
+	sheets := t.Sheet
+	if t.LastSheet != "" {
+		sheets += ":" + t.LastSheet
+	}
+	text := directory + "[" + book + "]" + sheets
+	if directory == "" && !needsQuote(book) && !needsQuote(t.Sheet) && !needsQuote(t.LastSheet) {
+		return text + "!"
+	}
+	return "'" + strings.Replace(text, "'", "''", -1) + "'!"
+}
+
+// needsQuote returns true if the name has characters that need quotes around sheet names.
+func needsQuote(name string) bool {
+	for _, r := range name {
+		if !(r == '_' || r == '.' || (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r > 127) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteSheetName adds single quotes to a sheet name if it is needed in a formula.
+func quoteSheetName(name string) string {
+	if needsQuote(name) {
+		return "'" + strings.Replace(name, "'", "''", -1) + "'"
+	}
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		return "'" + name + "'"
+	}
+	if _, ok := parseCellAddress(name); ok {
+		return "'" + name + "'"
+	}
+	return name
+}
@@ -1,7 +1,11 @@
 package xlsxformula
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestOperatorsAndNumbers(t *testing.T) {
@@ -49,6 +53,131 @@ func TestJoinOperatorAndString(t *testing.T) {
 	}
 }
 
+func TestEscapedDoubleQuotation(t *testing.T) {
+	testCases := []struct {
+		formula string
+		text    string
+	}{
+		{`"He said ""hi"""`, `He said "hi"`},
+		{`""""`, `"`},
+		{`""`, ``},
+		{`"a""b""c"`, `a"b"c`},
+	}
+	for _, testCase := range testCases {
+		tokens, err := Tokenize(testCase.formula)
+		if err != nil || len(tokens) != 1 || tokens[0].Type != String {
+			t.Errorf("%s should be one String token, but %v %v", testCase.formula, tokens, err)
+			continue
+		}
+		if tokens[0].Text != testCase.text || tokens[0].Raw() != testCase.formula {
+			t.Errorf("%s should be %s, but %s (%s)", testCase.formula, testCase.text, tokens[0].Text, tokens[0].Raw())
+		}
+	}
+	tokens, _ := TokenizeWithOptions(`"x""y" & "z"`, TokenizeOptions{Trivia: true})
+	if len(tokens) != 3 || tokens[0].Raw() != `"x""y"` || tokens[2].Raw() != `"z"` {
+		t.Errorf("Raw() should return the source text, but %v", tokens)
+	}
+	if _, err := Tokenize(`"abc""`); err == nil {
+		t.Errorf("escaped closing quotation should be an error")
+	}
+}
+
+func TestPercentAndScientificNotation(t *testing.T) {
+	testCases := []struct {
+		formula  string
+		expected string
+	}{
+		{"50%", "Number:50 Operator:%"},
+		{"1.5E+10", "Number:1.5E+10"},
+		{"1e-3*2", "Number:1e-3 Operator:* Number:2"},
+		{".5+A1", "Number:.5 Operator:+ Range:A1"},
+		{"1E+", "Name:1E Operator:+"},
+		{"E1+2", "Range:E1 Operator:+ Number:2"},
+		{"1E +2", "Name:1E Operator:+ Number:2"},
+	}
+	for _, testCase := range testCases {
+		tokens, err := Tokenize(testCase.formula)
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", testCase.formula, err)
+			continue
+		}
+		actual := make([]string, len(tokens))
+		for i, token := range tokens {
+			actual[i] = token.Type.String() + ":" + token.Text
+		}
+		if strings.Join(actual, " ") != testCase.expected {
+			t.Errorf("%s should be %s, but %s", testCase.formula, testCase.expected, strings.Join(actual, " "))
+		}
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	tokens, err := Tokenize(`sum(a1:b2, true, False, #n/a, sheet1!$c$3, b:b, true(), Data1)`)
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	expected := []struct {
+		tokenType  TokenType
+		text       string
+		normalized string
+	}{
+		{Name, "sum", "SUM"},
+		{Range, "a1:b2", "A1:B2"},
+		{Bool, "true", "TRUE"},
+		{Bool, "False", "FALSE"},
+		{Error, "#n/a", "#N/A"},
+		{Range, "$c$3", "$C$3"},
+		{Range, "b:b", "B:B"},
+		{Name, "true", "TRUE"},
+		{Name, "Data1", "DATA1"},
+	}
+	var actual []*Token
+	for _, token := range tokens {
+		if token.Type != LParen && token.Type != RParen && token.Type != Comma {
+			actual = append(actual, token)
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Tokenize() should return %d values, but %d", len(expected), len(actual))
+	}
+	for i, e := range expected {
+		token := actual[i]
+		if token.Type != e.tokenType || token.Text != e.text || token.Normalized() != e.normalized {
+			t.Errorf("token %d should be %s %s (%s), but %s %s (%s)", i, e.tokenType, e.text, e.normalized, token.Type, token.Text, token.Normalized())
+		}
+	}
+	if tokens, _ := Tokenize(`"abc"`); tokens[0].Normalized() != "abc" {
+		t.Errorf("string should not be normalized, but %s", tokens[0].Normalized())
+	}
+}
+
+func TestRangeOutOfGrid(t *testing.T) {
+	tests := map[string]string{
+		"XFD1048576":  "Range",
+		"xfd1":        "Range",
+		"A1:XFD1":     "Range",
+		"ZZZ1":        "Name",
+		"XFE1":        "Name",
+		"A1048577":    "Name",
+		"A1:A1048577": "Range Operator Name",
+		"1:1048577":   "Number Operator Number",
+	}
+	for formula, expected := range tests {
+		tokens, err := Tokenize(formula)
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		var types []string
+		for _, token := range tokens {
+			types = append(types, token.Type.String())
+		}
+		if actual := strings.Join(types, " "); actual != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, actual)
+		}
+	}
+}
+
 func TestRangeAndName(t *testing.T) {
 	tokens, err := Tokenize(`A1 ^ VARIABLE`)
 	if err != nil {
@@ -63,6 +192,199 @@ func TestRangeAndName(t *testing.T) {
 	}
 }
 
+func TestFunctionNameLikeRange(t *testing.T) {
+	tokens, err := Tokenize(`LOG10(A1)`)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	}
+	if len(tokens) != 4 {
+		t.Errorf("Parse() should return 4 tokens, but %d tokens", len(tokens))
+		return
+	}
+	if tokens[0].Type != Name || tokens[2].Type != Range {
+		t.Errorf("Node type is wrong: %s %s", tokens[0].Type.String(), tokens[2].Type.String())
+	}
+}
+
+func TestSheetReference(t *testing.T) {
+	tokens, err := Tokenize(`Sheet2!A1 + 'Q1 Data'!B3:C4 + 'It''s'!Total + Sheet1:Sheet3!$A$1`)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	}
+	if len(tokens) != 7 {
+		t.Errorf("Parse() should return 7 tokens, but %d tokens", len(tokens))
+		return
+	}
+	expected := []struct {
+		tokenType TokenType
+		text      string
+		sheet     string
+		lastSheet string
+		col       int
+	}{
+		{Range, "A1", "Sheet2", "", 1},
+		{Range, "B3:C4", "Q1 Data", "", 13},
+		{Name, "Total", "It's", "", 31},
+		{Range, "$A$1", "Sheet1", "Sheet3", 47},
+	}
+	for i, e := range expected {
+		token := tokens[i*2]
+		if token.Type != e.tokenType || token.Text != e.text || token.Sheet != e.sheet || token.LastSheet != e.lastSheet || token.Col != e.col {
+			t.Errorf("token %d should be %s %s!%s at %d, but %s %s!%s at %d", i, e.tokenType, e.sheet, e.text, e.col, token.Type, token.Sheet, token.Text, token.Col)
+		}
+	}
+	if prefix := tokens[2].SheetPrefix(); prefix != "'Q1 Data'!" {
+		t.Errorf("SheetPrefix() should be 'Q1 Data'!, but %s", prefix)
+	}
+	if prefix := tokens[6].SheetPrefix(); prefix != "Sheet1:Sheet3!" {
+		t.Errorf("SheetPrefix() should be Sheet1:Sheet3!, but %s", prefix)
+	}
+}
+
+func TestWorkbookReference(t *testing.T) {
+	testCases := []struct {
+		formula  string
+		expected string
+		prefix   string
+	}{
+		{"[Book1]Sheet1!A1", "Range:Book1:Sheet1:A1", "[Book1]Sheet1!"},
+		{"[1]Sheet1:Sheet3!$A$1:B3", "Range:1:Sheet1:Sheet3:$A$1:B3", "[1]Sheet1:Sheet3!"},
+		{"'[Book 1.xlsx]My Sheet'!B2", "Range:Book 1.xlsx:My Sheet:B2", "'[Book 1.xlsx]My Sheet'!"},
+		{`'C:\Data\[Book1.xlsx]Sheet1'!A:A`, `Range:C:\Data\Book1.xlsx:Sheet1:A:A`, `'C:\Data\[Book1.xlsx]Sheet1'!`},
+		{"[1]!TaxRate", "Name:1::TaxRate", "[1]!"},
+		{"[My Book.xlsx]!TaxRate", "Name:My Book.xlsx::TaxRate", "[My Book.xlsx]!"},
+		{"[/]!TaxRate", "Name:/::TaxRate", "[/]!"},
+	}
+	for _, testCase := range testCases {
+		tokens, err := Tokenize(testCase.formula)
+		if err != nil || len(tokens) != 1 {
+			t.Errorf("%s should be one token, but %v %v", testCase.formula, tokens, err)
+			continue
+		}
+		token := tokens[0]
+		sheets := token.Sheet
+		if token.LastSheet != "" {
+			sheets += ":" + token.LastSheet
+		}
+		actual := fmt.Sprintf("%s:%s:%s:%s", token.Type, token.Workbook, sheets, token.Text)
+		if actual != testCase.expected || token.SheetPrefix() != testCase.prefix {
+			t.Errorf("%s should be %s (%s), but %s (%s)", testCase.formula, testCase.expected, testCase.prefix, actual, token.SheetPrefix())
+		}
+	}
+	// brackets without sheet name are structured references
+	tokens, err := Tokenize("[Sales]+[@Amount]")
+	if err != nil || len(tokens) != 3 || tokens[0].Type != StructuredRef || tokens[2].Type != StructuredRef {
+		t.Errorf("structured references should not be workbooks, but %v %v", tokens, err)
+	}
+	for _, formula := range []string{`[1]!`, `[1]!A1`, `[1]Sheet1!`} {
+		if _, err := Tokenize(formula); err == nil {
+			t.Errorf("Tokenize(%s) should return error", formula)
+		}
+	}
+}
+
+func TestSheetReferenceError(t *testing.T) {
+	for _, formula := range []string{`'Sheet1`, `'Sheet1'A1`, `Sheet1!`, `''!A1`} {
+		if _, err := Tokenize(formula); err == nil {
+			t.Errorf("Tokenize(%s) should return error", formula)
+		}
+	}
+}
+
+func TestErrorLiteral(t *testing.T) {
+	tokens, err := Tokenize(`#N/A+#DIV/0!*Sheet1!#REF!&#NAME?,'My Sheet'!#REF!`)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	}
+	if len(tokens) != 9 {
+		t.Errorf("Parse() should return 9 tokens, but %d tokens", len(tokens))
+		return
+	}
+	expected := []string{"#N/A", "#DIV/0!", "#REF!", "#NAME?", "#REF!"}
+	for i, text := range expected {
+		token := tokens[i*2]
+		if token.Type != Error || token.Text != text {
+			t.Errorf("token %d should be %s, but %s %s", i, text, token.Type, token.Text)
+		}
+	}
+	if tokens[4].Sheet != "Sheet1" || tokens[8].Sheet != "My Sheet" {
+		t.Errorf("sheet name is wrong: %s, %s", tokens[4].Sheet, tokens[8].Sheet)
+	}
+	if _, err := Tokenize(`#FOO!`); err == nil {
+		t.Errorf("unknown error literal should be error")
+	}
+}
+
+func TestArrayConstant(t *testing.T) {
+	tokens, err := Tokenize(`{1,"a";TRUE,#N/A}`)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	}
+	expected := []TokenType{LBrace, Number, Comma, String, Semicolon, Bool, Comma, Error, RBrace}
+	if len(tokens) != len(expected) {
+		t.Errorf("Parse() should return %d tokens, but %d tokens", len(expected), len(tokens))
+		return
+	}
+	for i, tokenType := range expected {
+		if tokens[i].Type != tokenType {
+			t.Errorf("token %d should be %s, but %s", i, tokenType, tokens[i].Type)
+		}
+	}
+}
+
+func TestStructuredReference(t *testing.T) {
+	tests := map[string]StructuredReference{
+		`Sales[Amount]`:                     {Table: "Sales", FirstColumn: "Amount"},
+		`Sales[]`:                           {Table: "Sales"},
+		`Sales[#All]`:                       {Table: "Sales", Items: []string{"#All"}},
+		`Sales[[#Headers],[#data]]`:         {Table: "Sales", Items: []string{"#Headers", "#Data"}},
+		`Table1[[#This Row],[Amount]]`:      {Table: "Table1", Items: []string{"#This Row"}, FirstColumn: "Amount"},
+		`Table1[@Amount]`:                   {Table: "Table1", Items: []string{"#This Row"}, FirstColumn: "Amount"},
+		`Table1[@[Unit Price]]`:             {Table: "Table1", Items: []string{"#This Row"}, FirstColumn: "Unit Price"},
+		`Table1[@[Col1]:[Col3]]`:            {Table: "Table1", Items: []string{"#This Row"}, FirstColumn: "Col1", LastColumn: "Col3"},
+		`[@Amount]`:                         {Items: []string{"#This Row"}, FirstColumn: "Amount"},
+		`Sales[[Col1]:[Col3]]`:              {Table: "Sales", FirstColumn: "Col1", LastColumn: "Col3"},
+		`Sales[[#Totals], [Col1] : [Col3]]`: {Table: "Sales", Items: []string{"#Totals"}, FirstColumn: "Col1", LastColumn: "Col3"},
+		`Sales[Price '[USD'] ''net''  '#1]`: {Table: "Sales", FirstColumn: "Price [USD] 'net'  #1"},
+		`Table1['#Col]`:                     {Table: "Table1", FirstColumn: "#Col"},
+		`Table1[ [Col1] ]`:                  {Table: "Table1", FirstColumn: "Col1"},
+		`Table1[@ [Col1]:[Col2] ]`:          {Table: "Table1", Items: []string{"#This Row"}, FirstColumn: "Col1", LastColumn: "Col2"},
+		`Table1[[#Data],['#Col]]`:           {Table: "Table1", Items: []string{"#Data"}, FirstColumn: "#Col"},
+	}
+	for formula, expected := range tests {
+		tokens, err := Tokenize(formula)
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		if len(tokens) != 1 || tokens[0].Type != StructuredRef {
+			t.Errorf("%s should be one StructuredRef token, but %d tokens", formula, len(tokens))
+			continue
+		}
+		ref := tokens[0].Structured
+		if tokens[0].Text != formula || ref.Table != expected.Table || ref.FirstColumn != expected.FirstColumn || ref.LastColumn != expected.LastColumn || strings.Join(ref.Items, ",") != strings.Join(expected.Items, ",") {
+			t.Errorf("%s should be parsed as %#v, but %#v", formula, expected, *ref)
+		}
+	}
+	tokens, err := Tokenize(`SUM(Sales[Q1 Amount])*2`)
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if len(tokens) != 6 || tokens[2].Type != StructuredRef || tokens[2].Text != "Sales[Q1 Amount]" || tokens[2].Col != 5 {
+		t.Errorf("tokens are wrong: %v", tokens)
+	}
+}
+
+func TestStructuredReferenceError(t *testing.T) {
+	for _, formula := range []string{`Sales[Amount`, `Sales[#Foo]`, `Sales[[Col1],[Col2]]`, `Sales[[Col1]:]`, `Sales[[#All] [Col1]]`, `Sales[a[b]`} {
+		if _, err := Tokenize(formula); err == nil {
+			t.Errorf("Tokenize(%s) should return error", formula)
+		}
+	}
+}
+
 func TestCompare_1(t *testing.T) {
 	tokens, err := Tokenize(`1 = 10`)
 	if err != nil {
@@ -183,3 +505,123 @@ func TestMultiline(t *testing.T) {
 			tokens[3].Col, tokens[4].Col, tokens[5].Col)
 	}
 }
+
+func TestWhitespaces(t *testing.T) {
+	tokens, err := Tokenize("SUM(\n\tA1,\u00a0B1\r\n)")
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if len(tokens) != 6 {
+		t.Fatalf("Tokenize() should return 6 tokens, but %d tokens", len(tokens))
+	}
+	expected := [][2]int{{1, 1}, {1, 4}, {2, 2}, {2, 4}, {2, 6}, {3, 1}}
+	for i, token := range tokens {
+		if token.Line != expected[i][0] || token.Col != expected[i][1] {
+			t.Errorf("position of '%s' should be %d:%d, but %d:%d", token.Text, expected[i][0], expected[i][1], token.Line, token.Col)
+		}
+	}
+	if tokens[2].Text != "A1" || tokens[4].Text != "B1" {
+		t.Errorf("tab and NBSP should be separators, but '%s' '%s'", tokens[2].Text, tokens[4].Text)
+	}
+}
+
+// terminates fails the test if f doesn't return in a second.
+func terminates(t *testing.T, input string, f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%q doesn't terminate", input)
+	}
+}
+
+var fuzzCorpus = []string{
+	"SUM(A1:B3)*2",
+	"IF(A1>=10,\"a\",\"b\")",
+	"\n",
+	"1+\r\n\t2\u00a0",
+	"'My Sheet'!A1&Sheet1:Sheet3!B2",
+	"{1,2;3,4}",
+	"Sales[[#Headers],[Amount]]",
+	"#REF!+#N/A",
+	"\"",
+	"'",
+	"[",
+	"((",
+	"R[-1]C[2]",
+}
+
+func FuzzTokenize(f *testing.F) {
+	for _, input := range fuzzCorpus {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		terminates(t, input, func() {
+			Tokenize(input)
+			TokenizeWithOptions(input, TokenizeOptions{R1C1: true})
+			tokens, err := TokenizeWithOptions(input, TokenizeOptions{Trivia: true})
+			if err == nil && len(tokens) > 0 && utf8.ValidString(input) && FormatTokens(tokens) != input {
+				t.Errorf("FormatTokens() should be %q, but %q", input, FormatTokens(tokens))
+			}
+		})
+	})
+}
+
+func TestIntersectionOperator(t *testing.T) {
+	tokens, err := Tokenize("SUM(A1:C3  B2:D4) + Sales[Amount] (Q1) - SUM (A1)")
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	var operators []string
+	for _, token := range tokens {
+		if token.Type == Operator {
+			operators = append(operators, token.Text)
+		}
+	}
+	if strings.Join(operators, "|") != " |+| |-" {
+		t.Errorf("spaces between references should be intersection operators, but %q", operators)
+	}
+	if tokens[3].Col != 10 {
+		t.Errorf("intersection operator should be at 10, but %d", tokens[3].Col)
+	}
+}
+
+func TestRangeOperator(t *testing.T) {
+	testCases := []struct {
+		formula  string
+		expected string
+	}{
+		{"A1:B3", "Range:A1:B3"},
+		{"Sheet1:Sheet3!A1:B2", "Range:A1:B2"},
+		{"A1:INDEX(B1:B10,5)", "Range:A1 Operator:: Name:INDEX LParen:( Range:B1:B10 Comma:, Number:5 RParen:)"},
+		{"OFFSET(A1,1,1):C10", "Name:OFFSET LParen:( Range:A1 Comma:, Number:1 Comma:, Number:1 RParen:) Operator:: Range:C10"},
+		{"Name1:Name2", "Name:Name1 Operator:: Name:Name2"},
+		{"Sheet1!A1:Sheet1!B2", "Range:A1 Operator:: Range:B2"},
+		{"'My Sheet'!A1:'My Sheet'!B2", "Range:A1 Operator:: Range:B2"},
+		{"A1:Sheet1!B2", "Range:A1 Operator:: Range:B2"},
+		{"Name1:'My Sheet'!B2", "Name:Name1 Operator:: Range:B2"},
+		{"A:A", "Range:A:A"},
+		{"$B:$D", "Range:$B:$D"},
+		{"1:1", "Range:1:1"},
+		{"Sheet1!3:5", "Range:3:5"},
+		{"'My Sheet'!$C:E", "Range:$C:E"},
+	}
+	for _, testCase := range testCases {
+		tokens, err := Tokenize(testCase.formula)
+		if err != nil {
+			t.Errorf("%s: err should be nil, but %v", testCase.formula, err)
+			continue
+		}
+		var actual []string
+		for _, token := range tokens {
+			actual = append(actual, token.Type.String()+":"+token.Text)
+		}
+		if strings.Join(actual, " ") != testCase.expected {
+			t.Errorf("%s: tokens should be %s, but %s", testCase.formula, testCase.expected, strings.Join(actual, " "))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package xlsxformula
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Separators are the characters of formulas that depend on the locale of Excel. Zero fields are
+// the invariant (en-US) ones that xlsx files use. Locales with the decimal comma use ';' between
+// arguments like SUMME(A1;0,5).
+type Separators struct {
+	Argument    rune // between function arguments and the union operator (default ',')
+	Decimal     rune // decimal point of numbers (default '.')
+	ArrayColumn rune // between columns of array constants (default ',')
+	ArrayRow    rune // between rows of array constants (default ';')
+}
+
+// InvariantSeparators are the separators of en-US and xlsx files.
+var InvariantSeparators = Separators{Argument: ',', Decimal: '.', ArrayColumn: ',', ArrayRow: ';'}
+
+// normalize fills zero fields with the invariant separators.
+func (s Separators) normalize() Separators {
+	if s.Argument == 0 {
+		s.Argument = InvariantSeparators.Argument
+	}
+	if s.Decimal == 0 {
+		s.Decimal = InvariantSeparators.Decimal
+	}
+	if s.ArrayColumn == 0 {
+		s.ArrayColumn = InvariantSeparators.ArrayColumn
+	}
+	if s.ArrayRow == 0 {
+		s.ArrayRow = InvariantSeparators.ArrayRow
+	}
+	return s
+}
+
+// validate returns error if the separators can't be distinguished.
+func (s Separators) validate() error {
+	if s.Decimal == s.Argument || s.Decimal == s.ArrayColumn || s.Decimal == s.ArrayRow || s.ArrayColumn == s.ArrayRow {
+		return fmt.Errorf("separators are ambiguous: argument %q, decimal %q, array column %q, array row %q", s.Argument, s.Decimal, s.ArrayColumn, s.ArrayRow)
+	}
+	for _, r := range []rune{s.Argument, s.Decimal, s.ArrayColumn, s.ArrayRow} {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_\"'(){}[]!$:", r) {
+			return fmt.Errorf("%q can't be a separator", r)
+		}
+	}
+	return nil
+}
+
+// localize returns the text of the token with the separators. depth is the nest level of array constants.
+func (s Separators) localize(token *Token, depth int) string {
+	switch {
+	case token.Type == Number:
+		return strings.Replace(token.Text, ".", string(s.Decimal), 1)
+	case token.Type == Comma && depth > 0:
+		return string(s.ArrayColumn)
+	case token.Type == Comma, token.Type == Operator && token.Text == ",":
+		return string(s.Argument)
+	case token.Type == Semicolon:
+		return string(s.ArrayRow)
+	}
+	return formatToken(token)
+}
+
+// ConvertSeparators rewrites the separators of formula from one locale to another like
+// "WENN(A1>0,5;1;2)" into "WENN(A1>0.5,1,2)". Spaces, line breaks and other tokens are kept as they are.
+// Function names are not translated.
+func ConvertSeparators(formula string, from, to Separators) (string, error) {
+	to = to.normalize()
+	if err := to.validate(); err != nil {
+		return "", err
+	}
+	tokens, err := TokenizeWithOptions(formula, TokenizeOptions{Trivia: true, Separators: from})
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	depth := 0
+	for _, token := range tokens {
+		buffer.WriteString(token.Leading)
+		switch token.Type {
+		case Number, Comma, Semicolon:
+			buffer.WriteString(to.localize(token, depth))
+		default:
+			buffer.WriteString(token.Raw())
+		}
+		buffer.WriteString(token.Trailing)
+		if token.Type == LBrace {
+			depth++
+		} else if token.Type == RBrace && depth > 0 {
+			depth--
+		}
+	}
+	return buffer.String(), nil
+}
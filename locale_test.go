@@ -0,0 +1,82 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+var germanSeparators = Separators{Argument: ';', Decimal: ',', ArrayColumn: '.', ArrayRow: ';'}
+
+func TestTokenizeWithSeparators(t *testing.T) {
+	tokens, err := TokenizeWithOptions("SUMME({1,5.2;3,4};(A1;B2);,5;1,5E+10)", TokenizeOptions{Separators: germanSeparators})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	expected := []struct {
+		nodeType TokenType
+		text     string
+	}{
+		{Name, "SUMME"}, {LParen, "("}, {LBrace, "{"}, {Number, "1.5"}, {Comma, ","}, {Number, "2"}, {Semicolon, ";"},
+		{Number, "3.4"}, {RBrace, "}"}, {Comma, ","}, {LParen, "("}, {Range, "A1"}, {Comma, ","}, {Range, "B2"},
+		{RParen, ")"}, {Comma, ","}, {Number, ".5"}, {Comma, ","}, {Number, "1.5E+10"}, {RParen, ")"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("token count should be %d, but %d", len(expected), len(tokens))
+	}
+	for i, e := range expected {
+		if tokens[i].Type != e.nodeType || tokens[i].Text != e.text {
+			t.Errorf("token %d should be %v %s, but %v %s", i, e.nodeType, e.text, tokens[i].Type, tokens[i].Text)
+		}
+	}
+}
+
+func TestTokenizeWithSeparatorsError(t *testing.T) {
+	tests := map[string]Separators{
+		"A1,B1":    {Argument: ';', Decimal: ','},
+		"SUM(0.5)": germanSeparators,
+		"1":        {Decimal: ','},
+		"2":        {Argument: 'x'},
+	}
+	for formula, separators := range tests {
+		if _, err := TokenizeWithOptions(formula, TokenizeOptions{Separators: separators}); err == nil {
+			t.Errorf("%s with %v should be error, but nil", formula, separators)
+		}
+	}
+}
+
+func TestParseAndFormatWithSeparators(t *testing.T) {
+	node, err := ParseWithOptions("WENN(A1>0,5;{1.2;3.4};'x;y'!A1)", ParseOptions{Separators: germanSeparators})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if node.Type != Function || len(node.Children) != 3 || node.Children[2].Token.Sheet != "x;y" {
+		t.Errorf("WENN should have 3 arguments, but %s", node.String())
+	}
+	if formatted := Format(node, FormatOptions{}); formatted != "WENN(A1>0.5,{1,2;3,4},'x;y'!A1)" {
+		t.Errorf("invariant format should be WENN(A1>0.5,{1,2;3,4},'x;y'!A1), but %s", formatted)
+	}
+	if formatted := Format(node, FormatOptions{Spaces: true, Separators: germanSeparators}); formatted != "WENN(A1 > 0,5; {1.2;3.4}; 'x;y'!A1)" {
+		t.Errorf("localized format should be WENN(A1 > 0,5; {1.2;3.4}; 'x;y'!A1), but %s", formatted)
+	}
+	union, _ := ParseWithOptions("SUM((A1,B1))", ParseOptions{})
+	if formatted := Format(union, FormatOptions{Separators: germanSeparators}); formatted != "SUM((A1;B1))" {
+		t.Errorf("union should be SUM((A1;B1)), but %s", formatted)
+	}
+}
+
+func TestConvertSeparators(t *testing.T) {
+	localized := `=WENN(A1 > 0,5; "a,b;c";  {1.2;3,5} )`
+	invariant, err := ConvertSeparators(localized, germanSeparators, Separators{})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if expected := `=WENN(A1 > 0.5, "a,b;c",  {1,2;3.5} )`; invariant != expected {
+		t.Errorf("ConvertSeparators() should be %s, but %s", expected, invariant)
+	}
+	back, err := ConvertSeparators(invariant, Separators{}, germanSeparators)
+	if err != nil || back != localized {
+		t.Errorf("ConvertSeparators() should be %s, but %s (%v)", localized, back, err)
+	}
+	if _, err := ConvertSeparators("A1", Separators{}, Separators{Decimal: ','}); err == nil {
+		t.Errorf("ambiguous separators should be error, but nil")
+	}
+}
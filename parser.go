@@ -12,6 +12,13 @@ const (
 	Function NodeType = iota
 	Expression
 	SingleToken
+	BinaryOp     // operator with two operands: Children[0] Token.Text Children[1]
+	UnaryOp      // prefix operator (-, +) or postfix percent (%) with one operand: Token.Text Children[0]
+	Array        // array constant like {1,2;3,4}. Children are ArrayRow nodes
+	ArrayRow     // row of array constant. Children are SingleToken nodes of constants
+	Intersection // cells in both references like A1:C3 B2:D4: Children[0] Children[1]. Token is the space operator
+	Union        // all references of Children like (A1,B2). Token is the first comma operator
+	RangeOp      // area between references like A1:INDEX(B:B,5): Children[0] Children[1]. Token is the colon operator
 )
 
 func (nt NodeType) String() string {
@@ -22,6 +29,20 @@ func (nt NodeType) String() string {
 		return "Expression"
 	case SingleToken:
 		return "SingleToken"
+	case BinaryOp:
+		return "BinaryOp"
+	case UnaryOp:
+		return "UnaryOp"
+	case Array:
+		return "Array"
+	case ArrayRow:
+		return "ArrayRow"
+	case Intersection:
+		return "Intersection"
+	case Union:
+		return "Union"
+	case RangeOp:
+		return "RangeOp"
 	}
 	return "Unknown"
 }
@@ -58,7 +79,64 @@ func (node Node) String() string {
 		buffer.WriteByte(')')
 		return buffer.String()
 	case SingleToken:
-		return node.Token.Text
+		return node.Token.SheetPrefix() + node.Token.Text
+	case BinaryOp:
+		var buffer bytes.Buffer
+		buffer.WriteByte('(')
+		buffer.WriteString(node.Children[0].String())
+		buffer.WriteByte(' ')
+		buffer.WriteString(node.Token.Text)
+		buffer.WriteByte(' ')
+		buffer.WriteString(node.Children[1].String())
+		buffer.WriteByte(')')
+		return buffer.String()
+	case UnaryOp:
+		var buffer bytes.Buffer
+		buffer.WriteByte('(')
+		if node.Token.Text == "%" {
+			buffer.WriteString(node.Children[0].String())
+			buffer.WriteString(node.Token.Text)
+		} else {
+			buffer.WriteString(node.Token.Text)
+			buffer.WriteString(node.Children[0].String())
+		}
+		buffer.WriteByte(')')
+		return buffer.String()
+	case Intersection:
+		return "(" + node.Children[0].String() + " " + node.Children[1].String() + ")"
+	case RangeOp:
+		return "(" + node.Children[0].String() + ":" + node.Children[1].String() + ")"
+	case Union:
+		var buffer bytes.Buffer
+		buffer.WriteByte('(')
+		for i, child := range node.Children {
+			if i != 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(child.String())
+		}
+		buffer.WriteByte(')')
+		return buffer.String()
+	case Array:
+		var buffer bytes.Buffer
+		buffer.WriteByte('{')
+		for i, row := range node.Children {
+			if i != 0 {
+				buffer.WriteByte(';')
+			}
+			buffer.WriteString(row.String())
+		}
+		buffer.WriteByte('}')
+		return buffer.String()
+	case ArrayRow:
+		var buffer bytes.Buffer
+		for i, element := range node.Children {
+			if i != 0 {
+				buffer.WriteByte(',')
+			}
+			buffer.WriteString(element.String())
+		}
+		return buffer.String()
 	}
 	return ""
 }
@@ -74,8 +152,28 @@ func get(tokens []*Token, index int) *Token {
 	return nullToken
 }
 
+// ParseOptions controls the shape of the tree returned by ParseWithOptions.
+type ParseOptions struct {
+	// Flat keeps operators and operands as a flat sequence in Expression nodes
+	// (e.g. 10 + 20 * 30 becomes one Expression with 5 children) instead of
+	// building BinaryOp/UnaryOp nodes that follow Excel's operator precedence.
+	Flat bool
+	// R1C1 reads references in R1C1 notation like R[-1]C2 instead of A1 notation.
+	R1C1 bool
+	// Separators are the argument, decimal and array separators of the formula (TokenizeOptions.Separators).
+	Separators Separators
+}
+
+// Parse parses formula and returns flat Expression nodes. It is kept for
+// compatibility; use ParseWithOptions to get a precedence tree.
 func Parse(formula string) (*Node, error) {
-	tokens, err := Tokenize(formula)
+	return ParseWithOptions(formula, ParseOptions{Flat: true})
+}
+
+// ParseWithOptions parses formula. With the zero ParseOptions, operators become
+// BinaryOp and UnaryOp nodes grouped by Excel's precedence and associativity.
+func ParseWithOptions(formula string, options ParseOptions) (*Node, error) {
+	tokens, err := TokenizeWithOptions(formula, TokenizeOptions{R1C1: options.R1C1, Separators: options.Separators})
 	if err != nil {
 		return nil, err
 	}
@@ -128,11 +226,24 @@ func Parse(formula string) (*Node, error) {
 			}
 		case Comma:
 			if len(stack) < 2 {
-				return nil, fmt.Errorf("Unexpected comma ',' appears at %d:%d", token.Text, token.Line, token.Col)
+				return nil, fmt.Errorf("Unexpected comma ',' appears at %d:%d", token.Line, token.Col)
 			}
 			parentFunction := stack[len(stack)-2]
 			if parentFunction.Type != Function {
-				return nil, fmt.Errorf("Unexpected comma ',' appears outside of function arguments at %d:%d", token.Text, token.Line, token.Col)
+				// union operator in parentheses like (A1,B2)
+				if acceptValue {
+					return nil, fmt.Errorf("Unexpected comma ',' appears at %d:%d", token.Line, token.Col)
+				}
+				union := *token
+				union.Type = Operator
+				currentNode.Children = append(currentNode.Children, &Node{
+					Type:  SingleToken,
+					Token: &union,
+				})
+				acceptValue = true
+				lastOperator = token
+				i++
+				continue
 			}
 			nextParam := &Node{
 				Type: Expression,
@@ -143,7 +254,7 @@ func Parse(formula string) (*Node, error) {
 			acceptValue = true
 			lastOperator = token
 			i++
-		case Range:
+		case Range, StructuredRef:
 			if !acceptValue {
 				return nil, fmt.Errorf("Unexpected range '%s' appears at %d:%d", token.Text, token.Line, token.Col)
 			}
@@ -153,6 +264,16 @@ func Parse(formula string) (*Node, error) {
 			})
 			acceptValue = false
 			i++
+		case Error:
+			if !acceptValue {
+				return nil, fmt.Errorf("Unexpected error value '%s' appears at %d:%d", token.Text, token.Line, token.Col)
+			}
+			currentNode.Children = append(currentNode.Children, &Node{
+				Type:  SingleToken,
+				Token: token,
+			})
+			acceptValue = false
+			i++
 		case Bool:
 			if !acceptValue {
 				return nil, fmt.Errorf("Unexpected boolean value '%s' appears at %d:%d", token.Text, token.Line, token.Col)
@@ -184,7 +305,17 @@ func Parse(formula string) (*Node, error) {
 			acceptValue = false
 			i++
 		case Operator:
-			if acceptValue && token.Text != "-" && token.Text != "+" {
+			if token.Text == "%" {
+				// postfix operator like 50%
+				if acceptValue {
+					return nil, fmt.Errorf("Unexpected operator '%s' appears at %d:%d", token.Text, token.Line, token.Col)
+				}
+				currentNode.Children = append(currentNode.Children, &Node{
+					Type:  SingleToken,
+					Token: token,
+				})
+				i++
+			} else if acceptValue && token.Text != "-" && token.Text != "+" {
 				return nil, fmt.Errorf("Unexpected operator '%s' appears at %d:%d", token.Text, token.Line, token.Col)
 			} else {
 				currentNode.Children = append(currentNode.Children, &Node{
@@ -206,6 +337,17 @@ func Parse(formula string) (*Node, error) {
 			acceptValue = true
 			lastOperator = token
 			i++
+		case LBrace:
+			if !acceptValue {
+				return nil, fmt.Errorf("Unexpected left brace '{' appears at %d:%d", token.Line, token.Col)
+			}
+			array, next, err := parseArray(tokens, i)
+			if err != nil {
+				return nil, err
+			}
+			currentNode.Children = append(currentNode.Children, array)
+			acceptValue = false
+			i = next
 		case LParen:
 			if !acceptValue {
 				return nil, fmt.Errorf("Unexpected left paren '(' appears at %d:%d", token.Line, token.Col)
@@ -256,7 +398,78 @@ func Parse(formula string) (*Node, error) {
 	if len(stack) > 1 {
 		return nil, fmt.Errorf("The following nest defined at %d:%d is not closed yet: %s", stack[1].Token.Line, stack[1].Token.Col, stack[1].String())
 	}
-	return clean(stack[0]), nil
+	if options.Flat {
+		return clean(stack[0]), nil
+	}
+	return buildTree(clean(stack[0]))
+}
+
+// parseArray parses array constant like {1,2;3,4} starts at tokens[i] and returns the index after '}'.
+// Only numbers, strings, booleans, errors and negated numbers are allowed as elements.
+func parseArray(tokens []*Token, i int) (*Node, int, error) {
+	array := &Node{
+		Type:  Array,
+		Token: tokens[i],
+	}
+	row := &Node{
+		Type:  ArrayRow,
+		Token: tokens[i],
+	}
+	array.Children = append(array.Children, row)
+	i++
+	for {
+		token := get(tokens, i)
+		switch token.Type {
+		case Number, String, Bool, Error:
+			row.Children = append(row.Children, &Node{
+				Type:  SingleToken,
+				Token: token,
+			})
+			i++
+		case Operator:
+			next := get(tokens, i+1)
+			if token.Text != "-" || next.Type != Number {
+				return nil, 0, fmt.Errorf("Array constant can contain only numbers, strings, booleans and errors, but '%s' appears at %d:%d", token.Text, token.Line, token.Col)
+			}
+			row.Children = append(row.Children, &Node{
+				Type: SingleToken,
+				Token: &Token{
+					Type: Number,
+					Text: "-" + next.Text,
+					Line: token.Line,
+					Col:  token.Col,
+				},
+			})
+			i += 2
+		case Null:
+			return nil, 0, fmt.Errorf("Right brace '}' is missing. Orphan left brace appears at %d:%d", array.Token.Line, array.Token.Col)
+		default:
+			return nil, 0, fmt.Errorf("Array constant can contain only numbers, strings, booleans and errors, but '%s' appears at %d:%d", token.Text, token.Line, token.Col)
+		}
+		separator := get(tokens, i)
+		switch separator.Type {
+		case Comma:
+		case Semicolon:
+			if len(array.Children) > 1 && len(row.Children) != len(array.Children[0].Children) {
+				return nil, 0, fmt.Errorf("All rows of array constant should have the same number of elements at %d:%d", separator.Line, separator.Col)
+			}
+			row = &Node{
+				Type:  ArrayRow,
+				Token: separator,
+			}
+			array.Children = append(array.Children, row)
+		case RBrace:
+			if len(row.Children) != len(array.Children[0].Children) {
+				return nil, 0, fmt.Errorf("All rows of array constant should have the same number of elements at %d:%d", separator.Line, separator.Col)
+			}
+			return array, i + 1, nil
+		case Null:
+			return nil, 0, fmt.Errorf("Right brace '}' is missing. Orphan left brace appears at %d:%d", array.Token.Line, array.Token.Col)
+		default:
+			return nil, 0, fmt.Errorf("Unexpected '%s' appears in array constant at %d:%d", separator.Text, separator.Line, separator.Col)
+		}
+		i++
+	}
 }
 
 func clean(node *Node) *Node {
@@ -271,9 +484,206 @@ func clean(node *Node) *Node {
 }
 
 var isValue map[TokenType]bool = map[TokenType]bool{
-	Number: true,
-	String: true,
-	Bool:   true,
-	Range:  true,
-	Name:   true,
+	Number:        true,
+	String:        true,
+	Bool:          true,
+	Error:         true,
+	Range:         true,
+	Name:          true,
+	StructuredRef: true,
+}
+
+// Operator precedence of binary operators. Bigger binds tighter. Unary + and -
+// bind tighter than all of them (Excel evaluates -2^2 as 4).
+var binaryPrecedence map[string]int = map[string]int{
+	"=":  1,
+	"<>": 1,
+	"<":  1,
+	">":  1,
+	"<=": 1,
+	">=": 1,
+	"&":  2,
+	"+":  3,
+	"-":  3,
+	"*":  4,
+	"/":  4,
+	"^":  5,
+	",":  7, // union
+	" ":  8, // intersection
+	":":  9, // range
+}
+
+// referencePrecedence is the precedence of the union operator. Reference operators bind tighter than
+// unary - (-A1 B1 is -(A1 B1)).
+const referencePrecedence = 7
+
+// percentPrecedence is the precedence of the postfix % operator. It binds tighter than ^ and looser than
+// unary - (-5%^2 is ((-5)%)^2).
+const percentPrecedence = 6
+
+func isOperatorNode(node *Node) bool {
+	return node.Type == SingleToken && (node.Token.Type == Operator || node.Token.Type == Comparator)
+}
+
+// buildTree converts flat Expression nodes into BinaryOp/UnaryOp trees. The source nodes are not modified.
+func buildTree(node *Node) (*Node, error) {
+	switch node.Type {
+	case Function:
+		function := &Node{
+			Type:     Function,
+			Token:    node.Token,
+			Children: make([]*Node, len(node.Children)),
+		}
+		for i, param := range node.Children {
+			child, err := buildTree(param)
+			if err != nil {
+				return nil, err
+			}
+			function.Children[i] = child
+		}
+		return function, nil
+	case Expression:
+		if len(node.Children) == 0 {
+			// missing function argument like the second one of IF(A1,,2)
+			return node, nil
+		}
+		children := make([]*Node, len(node.Children))
+		for i, child := range node.Children {
+			if isOperatorNode(child) {
+				children[i] = child
+				continue
+			}
+			converted, err := buildTree(child)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = converted
+		}
+		builder := &treeBuilder{nodes: children}
+		result, err := builder.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		if builder.index < len(children) {
+			token := children[builder.index].Token
+			return nil, fmt.Errorf("Unexpected '%s' appears at %d:%d", token.Text, token.Line, token.Col)
+		}
+		return result, nil
+	}
+	return node, nil
+}
+
+// isReferenceOperand returns false if node can't be a reference like a number or a string.
+func isReferenceOperand(node *Node) bool {
+	switch node.Type {
+	case SingleToken:
+		return node.Token.Type == Range || node.Token.Type == Name || node.Token.Type == StructuredRef
+	case Function, Expression, Intersection, Union, RangeOp:
+		return true
+	}
+	return false
+}
+
+type treeBuilder struct {
+	nodes []*Node
+	index int
+}
+
+// binary parses operands joined by binary operators whose precedence is minPrecedence or more.
+// All of Excel's binary operators are left associative (2^3^2 is 64).
+func (b *treeBuilder) binary(minPrecedence int) (*Node, error) {
+	left, err := b.unary()
+	if err != nil {
+		return nil, err
+	}
+	for b.index < len(b.nodes) {
+		operator := b.nodes[b.index]
+		if !isOperatorNode(operator) {
+			return nil, fmt.Errorf("Operator is needed before '%s' at %d:%d", operator.String(), operator.Token.Line, operator.Token.Col)
+		}
+		if operator.Token.Text == "%" {
+			if percentPrecedence < minPrecedence {
+				break
+			}
+			b.index++
+			left = &Node{
+				Type:     UnaryOp,
+				Token:    operator.Token,
+				Children: []*Node{left},
+			}
+			continue
+		}
+		precedence, ok := binaryPrecedence[operator.Token.Text]
+		if !ok {
+			return nil, fmt.Errorf("Unexpected operator '%s' appears at %d:%d", operator.Token.Text, operator.Token.Line, operator.Token.Col)
+		}
+		if precedence < minPrecedence {
+			break
+		}
+		b.index++
+		right, err := b.binary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch operator.Token.Text {
+		case ":":
+			for _, operand := range []*Node{left, right} {
+				if !isReferenceOperand(operand) {
+					return nil, fmt.Errorf("Reference is needed for range operator ':' at %d:%d", operator.Token.Line, operator.Token.Col)
+				}
+			}
+			left = &Node{
+				Type:     RangeOp,
+				Token:    operator.Token,
+				Children: []*Node{left, right},
+			}
+		case " ":
+			left = &Node{
+				Type:     Intersection,
+				Token:    operator.Token,
+				Children: []*Node{left, right},
+			}
+		case ",":
+			if left.Type == Union {
+				left.Children = append(left.Children, right)
+			} else {
+				left = &Node{
+					Type:     Union,
+					Token:    operator.Token,
+					Children: []*Node{left, right},
+				}
+			}
+		default:
+			left = &Node{
+				Type:     BinaryOp,
+				Token:    operator.Token,
+				Children: []*Node{left, right},
+			}
+		}
+	}
+	return left, nil
+}
+
+func (b *treeBuilder) unary() (*Node, error) {
+	if b.index == len(b.nodes) {
+		last := b.nodes[len(b.nodes)-1].Token
+		return nil, fmt.Errorf("Any name, range or value is needed after '%s' at %d:%d", last.Text, last.Line, last.Col)
+	}
+	node := b.nodes[b.index]
+	b.index++
+	if !isOperatorNode(node) {
+		return node, nil
+	}
+	if node.Token.Text != "-" && node.Token.Text != "+" {
+		return nil, fmt.Errorf("Unexpected operator '%s' appears at %d:%d", node.Token.Text, node.Token.Line, node.Token.Col)
+	}
+	operand, err := b.binary(referencePrecedence)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{
+		Type:     UnaryOp,
+		Token:    node.Token,
+		Children: []*Node{operand},
+	}, nil
 }
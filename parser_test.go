@@ -183,3 +183,164 @@ func TestParseErrorTooManyRParen(t *testing.T) {
 		t.Errorf("err should not be nil")
 	}
 }
+
+func TestParseTreePrecedence(t *testing.T) {
+	node, err := ParseWithOptions("10 + 20 / 40 * 50 ^ 2", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.Type != BinaryOp || node.Token.Text != "+" {
+		t.Errorf("root node should be '+' BinaryOp, but %s '%s'", node.Type.String(), node.Token.Text)
+	} else if node.String() != "(10 + ((20 / 40) * (50 ^ 2)))" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseTreeLeftAssociative(t *testing.T) {
+	node, err := ParseWithOptions("2 ^ 3 ^ 2 - 1 - 1", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.String() != "((((2 ^ 3) ^ 2) - 1) - 1)" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseTreeUnaryMinusBindsTighterThanPower(t *testing.T) {
+	node, err := ParseWithOptions("-2 ^ 2", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.Type != BinaryOp || node.Children[0].Type != UnaryOp {
+		t.Errorf("unary minus should be the left operand of '^': %s", node.String())
+	} else if node.String() != "((-2) ^ 2)" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseTreeSequentialUnaryOperators(t *testing.T) {
+	node, err := ParseWithOptions("10 * - -10", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.String() != "(10 * (-(-10)))" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseTreePercent(t *testing.T) {
+	tests := map[string]string{
+		"50%":         "(50%)",
+		"-5%^2":       "(((-5)%) ^ 2)",
+		"2^50%":       "(2 ^ (50%))",
+		"A1%%*3":      "(((A1%)%) * 3)",
+		"SUM(A1:B2)%": "(SUM(A1:B2)%)",
+		"1.5E+10-.5":  "(1.5E+10 - .5)",
+	}
+	for formula, expected := range tests {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+		} else if node.String() != expected {
+			t.Errorf("%s should be %s, but %s", formula, expected, node.String())
+		}
+	}
+	for _, formula := range []string{"%", "%5", "1+%", "{50%}"} {
+		if _, err := ParseWithOptions(formula, ParseOptions{}); err == nil {
+			t.Errorf("%s should be error", formula)
+		}
+	}
+}
+
+func TestParseTreeComparatorAndConcat(t *testing.T) {
+	node, err := ParseWithOptions(`"a" & 1 + 2 = "a3"`, ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.String() != "((a & (1 + 2)) = a3)" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseTreeParenAndFunctionArguments(t *testing.T) {
+	node, err := ParseWithOptions("(1 + 2) * SUM(3 - 4 * 5, -A1)", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.String() != "((1 + 2) * SUM((3 - (4 * 5)), (-A1)))" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseSheetReference(t *testing.T) {
+	node, err := ParseWithOptions("SUM('Q1 Data'!B3:B5, Sheet1:Sheet3!A1) * Sheet2!Rate", ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	} else if node.String() != "(SUM('Q1 Data'!B3:B5, Sheet1:Sheet3!A1) * Sheet2!Rate)" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+}
+
+func TestParseArrayConstant(t *testing.T) {
+	node, err := ParseWithOptions(`INDEX({"a","b";"c","d"}, 2, 1) & SUM({1,-2,3} * 2)`, ParseOptions{})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	} else if node.String() != "(INDEX({a,b;c,d}, 2, 1) & SUM(({1,-2,3} * 2)))" {
+		t.Errorf("tree is wrong: %s", node.String())
+	}
+	array := node.Children[0].Children[0]
+	if array.Type != Array || len(array.Children) != 2 || array.Children[1].Type != ArrayRow || len(array.Children[1].Children) != 2 {
+		t.Errorf("array node is wrong: %s", array.String())
+	}
+}
+
+func TestParseArrayConstantError(t *testing.T) {
+	for _, formula := range []string{`{1,2;3}`, `{1;2,3}`, `{1,A1}`, `{1+2}`, `{1,,2}`, `{1,2`, `{}`, `{(1)}`, `1{2}`} {
+		if _, err := ParseWithOptions(formula, ParseOptions{}); err == nil {
+			t.Errorf("ParseWithOptions(%s) should return error", formula)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, input := range fuzzCorpus {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		terminates(t, input, func() {
+			Parse(input)
+			ParseWithOptions(input, ParseOptions{})
+			ParseWithOptions(input, ParseOptions{R1C1: true})
+		})
+	})
+}
+
+func TestParseIntersectionAndUnion(t *testing.T) {
+	node, err := ParseWithOptions("SUM((A1:B2,C3) D1:D4, -A1 B1)", ParseOptions{})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	intersection := node.Children[0]
+	if intersection.Type != Intersection || intersection.Children[0].Type != Union || len(intersection.Children[0].Children) != 2 {
+		t.Errorf("first argument should be intersection of union, but %s", intersection.String())
+	}
+	negative := node.Children[1]
+	if negative.Type != UnaryOp || negative.Children[0].Type != Intersection {
+		t.Errorf("intersection should bind tighter than unary minus, but %s", negative.String())
+	}
+	node, err = ParseWithOptions("(A1,B1,C1:C3)", ParseOptions{})
+	if err != nil || node.Type != Union || len(node.Children) != 3 {
+		t.Errorf("union should have 3 areas, but %v %v", node, err)
+	}
+	if _, err := Parse("A1,B1"); err == nil {
+		t.Errorf("comma outside of parentheses should be error")
+	}
+}
+
+func TestParseRangeOperator(t *testing.T) {
+	node, err := ParseWithOptions("-A1:INDEX(B1:B10,5) C1:C3", ParseOptions{})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if node.Type != UnaryOp || node.Children[0].Type != Intersection || node.Children[0].Children[0].Type != RangeOp {
+		t.Errorf("range operator should bind tighter than intersection, but %s", node.String())
+	}
+	if _, err := ParseWithOptions(`A1:"x"`, ParseOptions{}); err == nil {
+		t.Errorf("string operand of range operator should be error")
+	}
+}
@@ -0,0 +1,196 @@
+package xlsxformula
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const r1c1Part = `(R(\[-?[0-9]+\]|[1-9][0-9]*)?C(\[-?[0-9]+\]|[1-9][0-9]*)?|R(\[-?[0-9]+\]|[1-9][0-9]*)?|C(\[-?[0-9]+\]|[1-9][0-9]*)?)`
+
+var r1c1Pattern *regexp.Regexp = regexp.MustCompile(`(?i)^` + r1c1Part + `(:` + r1c1Part + `)?$`)
+
+// r1c1Offset returns the index after "[-1]" starts at source[index]. It returns 0 if it is not an offset.
+func r1c1Offset(source []rune, index int) int {
+	i := index + 1
+	if i < len(source) && source[i] == '-' {
+		i++
+	}
+	start := i
+	for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+		i++
+	}
+	if i == start || i >= len(source) || source[i] != ']' {
+		return 0
+	}
+	return i + 1
+}
+
+// r1c1Address is a parsed R1C1 style reference. Row and Col are offsets from the
+// anchor cell if they are relative. HasRow or HasCol is false for a whole column or row.
+type r1c1Address struct {
+	Row    int
+	Col    int
+	RowAbs bool
+	ColAbs bool
+	HasRow bool
+	HasCol bool
+}
+
+func (r r1c1Address) String() string {
+	var builder strings.Builder
+	write := func(prefix byte, value int, abs bool) {
+		builder.WriteByte(prefix)
+		if abs {
+			builder.WriteString(strconv.Itoa(value))
+		} else if value != 0 {
+			builder.WriteByte('[')
+			builder.WriteString(strconv.Itoa(value))
+			builder.WriteByte(']')
+		}
+	}
+	if r.HasRow {
+		write('R', r.Row, r.RowAbs)
+	}
+	if r.HasCol {
+		write('C', r.Col, r.ColAbs)
+	}
+	return builder.String()
+}
+
+// parseR1C1Address parses one side of R1C1 range like "R[-1]C2".
+func parseR1C1Address(text string) (r1c1Address, bool) {
+	var address r1c1Address
+	read := func(prefix byte) (int, bool, bool) {
+		if text == "" || text[0] != prefix {
+			return 0, false, false
+		}
+		text = text[1:]
+		if strings.HasPrefix(text, "[") {
+			end := strings.IndexByte(text, ']')
+			if end < 0 {
+				return 0, false, false
+			}
+			value, err := strconv.Atoi(text[1:end])
+			text = text[end+1:]
+			return value, false, err == nil
+		}
+		i := 0
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return 0, false, true
+		}
+		value, err := strconv.Atoi(text[:i])
+		text = text[i:]
+		return value, true, err == nil
+	}
+	var ok bool
+	if address.Row, address.RowAbs, ok = read('R'); ok {
+		address.HasRow = true
+	}
+	if address.Col, address.ColAbs, ok = read('C'); ok {
+		address.HasCol = true
+	}
+	return address, text == "" && (address.HasRow || address.HasCol)
+}
+
+// ToR1C1 returns a copy of node whose A1 style Range tokens are converted into R1C1 notation.
+// row and col are the position of the cell that has the formula. The same formula copied to
+// other cells becomes the same R1C1 formula.
+func ToR1C1(node *Node, row, col int) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("node is nil")
+	}
+	return transformTokens(node, func(token *Token) (*Token, error) {
+		if token.Type != Range {
+			return token, nil
+		}
+		parts := strings.Split(token.Text, ":")
+		var whole bool
+		for i, part := range parts {
+			address, ok := parseReferencePart(part)
+			if !ok {
+				return nil, fmt.Errorf("Invalid range '%s' at %d:%d", token.Text, token.Line, token.Col)
+			}
+			whole = address.Row == 0 || address.Col == 0
+			converted := r1c1Address{
+				Row:    address.Row,
+				Col:    address.Col,
+				RowAbs: address.RowAbs,
+				ColAbs: address.ColAbs,
+				HasRow: address.Row != 0,
+				HasCol: address.Col != 0,
+			}
+			if !address.RowAbs {
+				converted.Row -= row
+			}
+			if !address.ColAbs {
+				converted.Col -= col
+			}
+			parts[i] = converted.String()
+		}
+		if len(parts) == 2 && parts[0] == parts[1] && whole {
+			// 2:2 is R2, A:A is C1 in R1C1 notation
+			parts = parts[:1]
+		}
+		result := *token
+		result.Text = strings.Join(parts, ":")
+		return &result, nil
+	})
+}
+
+// ToA1 returns a copy of node whose R1C1 style Range tokens (parsed with ParseOptions{R1C1: true})
+// are converted into A1 notation. row and col are the position of the cell that has the formula.
+// A reference out of the sheet becomes #REF! error.
+func ToA1(node *Node, row, col int) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("node is nil")
+	}
+	return transformTokens(node, func(token *Token) (*Token, error) {
+		if token.Type != Range {
+			return token, nil
+		}
+		parts := strings.Split(token.Text, ":")
+		var whole bool
+		for i, part := range parts {
+			address, ok := parseR1C1Address(strings.ToUpper(part))
+			if !ok {
+				return nil, fmt.Errorf("Invalid R1C1 range '%s' at %d:%d", token.Text, token.Line, token.Col)
+			}
+			whole = !address.HasRow || !address.HasCol
+			converted := cellAddress{
+				Row:    address.Row,
+				Col:    address.Col,
+				RowAbs: address.RowAbs,
+				ColAbs: address.ColAbs,
+			}
+			if address.HasRow && !address.RowAbs {
+				converted.Row += row
+			}
+			if address.HasCol && !address.ColAbs {
+				converted.Col += col
+			}
+			if address.HasRow && (converted.Row < 1 || converted.Row > MaxRows) || address.HasCol && (converted.Col < 1 || converted.Col > MaxColumns) {
+				return refErrorToken(token), nil
+			}
+			if !address.HasRow {
+				converted.Row = 0
+			}
+			if !address.HasCol {
+				converted.Col = 0
+			}
+			parts[i] = converted.String()
+		}
+		if len(parts) == 1 && whole {
+			// R2 is 2:2, C1 is A:A in A1 notation
+			parts = append(parts, parts[0])
+		}
+		result := *token
+		result.Text = strings.Join(parts, ":")
+		return &result, nil
+	})
+}
@@ -0,0 +1,97 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+func TestTokenizeR1C1(t *testing.T) {
+	tokens, err := TokenizeWithOptions(`SUM(R[-1]C:R2C[3],Sheet2!RC1,R,C[-2],R1:R3)+ROUND(1)`, TokenizeOptions{R1C1: true})
+	if err != nil {
+		t.Errorf("err should be nil, but %v", err)
+		return
+	}
+	expected := []string{"R[-1]C:R2C[3]", "RC1", "R", "C[-2]", "R1:R3"}
+	for i, text := range expected {
+		token := tokens[i*2+2]
+		if token.Type != Range || token.Text != text {
+			t.Errorf("token %d should be Range %s, but %s %s", i, text, token.Type, token.Text)
+		}
+	}
+	if tokens[4].Sheet != "Sheet2" {
+		t.Errorf("sheet should be Sheet2, but %s", tokens[4].Sheet)
+	}
+	if tokens[13].Type != Name || tokens[13].Text != "ROUND" {
+		t.Errorf("function name should be Name, but %s %s", tokens[13].Type, tokens[13].Text)
+	}
+	tokens, err = Tokenize(`A1+R1C1`)
+	if err != nil || tokens[0].Type != Range || tokens[2].Type != Name {
+		t.Errorf("R1C1 reference should be a name in A1 mode: %v", err)
+	}
+}
+
+func TestToR1C1(t *testing.T) {
+	tests := map[string]string{
+		"A2+$B$1":          "R[-1]C[-2]+R1C2",
+		"SUM($A1:B$2)*C3":  "SUM(R[-2]C1:R2C[-1])*RC",
+		"Sheet2!C4&Rate":   "Sheet2!R[1]C&Rate",
+		"SUM(A1:A2,A1:A1)": "SUM(R[-2]C[-2]:R[-1]C[-2],R[-2]C[-2]:R[-2]C[-2])",
+		`IF(A1,"A1",C3)`:   `IF(R[-2]C[-2],"A1",RC)`,
+		"$A$1048576+XFD3":  "R1048576C1+RC[16381]",
+	}
+	for formula, expected := range tests {
+		node, err := ParseWithOptions(formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		converted, err := ToR1C1(node, 3, 3)
+		if err != nil {
+			t.Errorf("err of ToR1C1(%s) should be nil, but %v", formula, err)
+		} else if formatted := Format(converted, FormatOptions{}); formatted != expected {
+			t.Errorf("ToR1C1(%s) should be %s, but %s", formula, expected, formatted)
+		}
+	}
+}
+
+func TestToA1(t *testing.T) {
+	tests := map[string]string{
+		"R[-1]C[-2]+R1C2":         "A2+$B$1",
+		"SUM(R[-2]C1:R2C[-1])*RC": "SUM($A1:B$2)*C3",
+		"Sheet2!R[1]C&Rate":       "Sheet2!C4&Rate",
+		"R[-3]C+RC[-3]":           "#REF!+#REF!",
+		"SUM(R2,C[-1],R1:R[1])":   "SUM($2:$2,B:B,$1:4)",
+		"sum(r[-1]c[-2],rc)":      "SUM(A2,C3)",
+	}
+	for formula, expected := range tests {
+		node, err := ParseWithOptions(formula, ParseOptions{R1C1: true})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", formula, err)
+			continue
+		}
+		converted, err := ToA1(node, 3, 3)
+		if err != nil {
+			t.Errorf("err of ToA1(%s) should be nil, but %v", formula, err)
+		} else if formatted := Format(converted, FormatOptions{}); formatted != expected {
+			t.Errorf("ToA1(%s) should be %s, but %s", formula, expected, formatted)
+		}
+	}
+}
+
+func TestR1C1DetectsCopiedFormula(t *testing.T) {
+	formulas := []string{"A1*$B$1+SUM(C1:C3)", "A2*$B$1+SUM(C2:C4)", "A3*$B$1+SUM(C3:C5)"}
+	var first string
+	for i, formula := range formulas {
+		node, _ := ParseWithOptions(formula, ParseOptions{})
+		converted, err := ToR1C1(node, i+1, 4)
+		if err != nil {
+			t.Errorf("err should be nil, but %v", err)
+			return
+		}
+		r1c1 := Format(converted, FormatOptions{})
+		if i == 0 {
+			first = r1c1
+		} else if r1c1 != first {
+			t.Errorf("copied formula should be the same in R1C1: %s and %s", first, r1c1)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package xlsxformula
+
+import (
+	"strings"
+)
+
+// ReferenceKind is the shape of a reference.
+type ReferenceKind int
+
+const (
+	CellReference   ReferenceKind = iota // single cell like A1
+	AreaReference                        // rectangle like A1:B3
+	ColumnReference                      // whole columns like A:A or $B:$D
+	RowReference                         // whole rows like 1:1 or $3:$5
+)
+
+func (k ReferenceKind) String() string {
+	switch k {
+	case CellReference:
+		return "Cell"
+	case AreaReference:
+		return "Area"
+	case ColumnReference:
+		return "Column"
+	case RowReference:
+		return "Row"
+	}
+	return "Unknown"
+}
+
+// Reference is a parsed A1 style Range token. Rows and columns are 1 origin and the bounds are
+// explicit: whole columns have rows from 1 to MaxRows and whole rows have columns from 1 to MaxColumns.
+// The first cell is always the top-left even if the text is written like B3:A1.
+type Reference struct {
+	Kind        ReferenceKind
+	Workbook    string // external workbook of [Book1]Sheet1!A1. Empty for the same workbook
+	Sheet       string // empty if the reference has no sheet (the sheet of the formula)
+	LastSheet   string // last sheet of 3D reference like Sheet1:Sheet3!A1
+	FirstRow    int
+	FirstCol    int
+	LastRow     int
+	LastCol     int
+	FirstRowAbs bool // $ of the first row like A$1
+	FirstColAbs bool // $ of the first column like $A1
+	LastRowAbs  bool
+	LastColAbs  bool
+}
+
+// Reference parses the text of a Range token in A1 notation. It returns false for other tokens.
+// Text is parsed on each call, so it follows edits of the token. Range tokens from Tokenize are always
+// valid, but it returns false if Text was replaced with a text that is not a reference.
+func (t *Token) Reference() (Reference, bool) {
+	if t.Type != Range {
+		return Reference{}, false
+	}
+	start, end, ok := parseArea(t.Text)
+	if !ok {
+		return Reference{}, false
+	}
+	reference := Reference{
+		Kind:        AreaReference,
+		Workbook:    t.Workbook,
+		Sheet:       t.Sheet,
+		LastSheet:   t.LastSheet,
+		FirstRow:    start.Row,
+		FirstCol:    start.Col,
+		LastRow:     end.Row,
+		LastCol:     end.Col,
+		FirstRowAbs: start.RowAbs,
+		FirstColAbs: start.ColAbs,
+		LastRowAbs:  end.RowAbs,
+		LastColAbs:  end.ColAbs,
+	}
+	parts := strings.Split(t.Text, ":")
+	first, _ := parseReferencePart(parts[0])
+	last, _ := parseReferencePart(parts[len(parts)-1])
+	switch {
+	case len(parts) == 1:
+		reference.Kind = CellReference
+	case first.Row == 0 && last.Row == 0:
+		reference.Kind = ColumnReference
+	case first.Col == 0 && last.Col == 0:
+		reference.Kind = RowReference
+	}
+	return reference, true
+}
+
+// String returns the reference in A1 notation like "Sheet1!$A$1:B3", "$B:$D" or "3:5".
+func (r Reference) String() string {
+	first := cellAddress{Row: r.FirstRow, Col: r.FirstCol, RowAbs: r.FirstRowAbs, ColAbs: r.FirstColAbs}
+	last := cellAddress{Row: r.LastRow, Col: r.LastCol, RowAbs: r.LastRowAbs, ColAbs: r.LastColAbs}
+	switch r.Kind {
+	case ColumnReference:
+		first.Row, last.Row = 0, 0
+	case RowReference:
+		first.Col, first.ColAbs, last.Col, last.ColAbs = 0, false, 0, false
+	}
+	prefix := (&Token{Workbook: r.Workbook, Sheet: r.Sheet, LastSheet: r.LastSheet}).SheetPrefix()
+	if r.Kind == CellReference {
+		return prefix + first.String()
+	}
+	return prefix + first.String() + ":" + last.String()
+}
+
+// Rows returns the number of rows.
+func (r Reference) Rows() int {
+	return r.LastRow - r.FirstRow + 1
+}
+
+// Cols returns the number of columns.
+func (r Reference) Cols() int {
+	return r.LastCol - r.FirstCol + 1
+}
+
+// Size returns the number of cells.
+func (r Reference) Size() int {
+	return r.Rows() * r.Cols()
+}
+
+// Contains returns true if the cell is in the bounds. Sheets are not checked.
+func (r Reference) Contains(row, col int) bool {
+	return r.FirstRow <= row && row <= r.LastRow && r.FirstCol <= col && col <= r.LastCol
+}
+
+// ContainsReference returns true if other is on the same sheets and all the cells of other are in r.
+// References without sheet are on the same sheet only with each other.
+func (r Reference) ContainsReference(other Reference) bool {
+	return r.sameSheets(other) && r.Contains(other.FirstRow, other.FirstCol) && r.Contains(other.LastRow, other.LastCol)
+}
+
+// Intersect returns the cells in both references like the intersection operator (A1:C3 B2:D4 is B2:C3).
+// It returns false if the references are on different sheets or don't overlap. The result has no $.
+func (r Reference) Intersect(other Reference) (Reference, bool) {
+	if !r.sameSheets(other) {
+		return Reference{}, false
+	}
+	result := Reference{
+		Kind:      AreaReference,
+		Workbook:  r.Workbook,
+		Sheet:     r.Sheet,
+		LastSheet: r.LastSheet,
+		FirstRow:  maxInt(r.FirstRow, other.FirstRow),
+		FirstCol:  maxInt(r.FirstCol, other.FirstCol),
+		LastRow:   minInt(r.LastRow, other.LastRow),
+		LastCol:   minInt(r.LastCol, other.LastCol),
+	}
+	if result.FirstRow > result.LastRow || result.FirstCol > result.LastCol {
+		return Reference{}, false
+	}
+	switch {
+	case result.Size() == 1:
+		result.Kind = CellReference
+	case r.Kind == other.Kind && (r.Kind == ColumnReference || r.Kind == RowReference):
+		result.Kind = r.Kind
+	}
+	return result, true
+}
+
+// Each calls f for each cell from the top-left in row-major order until f returns false.
+func (r Reference) Each(f func(row, col int) bool) {
+	for row := r.FirstRow; row <= r.LastRow; row++ {
+		for col := r.FirstCol; col <= r.LastCol; col++ {
+			if !f(row, col) {
+				return
+			}
+		}
+	}
+}
+
+func (r Reference) sameSheets(other Reference) bool {
+	return strings.EqualFold(r.Workbook, other.Workbook) && strings.EqualFold(r.Sheet, other.Sheet) && strings.EqualFold(r.LastSheet, other.LastSheet)
+}
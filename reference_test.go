@@ -0,0 +1,117 @@
+package xlsxformula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenReference(t *testing.T) {
+	testCases := []struct {
+		formula  string
+		kind     ReferenceKind
+		expected [4]int
+	}{
+		{"B3", CellReference, [4]int{3, 2, 3, 2}},
+		{"$C$5:A1", AreaReference, [4]int{1, 1, 5, 3}},
+		{"A:A", ColumnReference, [4]int{1, 1, MaxRows, 1}},
+		{"$B:$D", ColumnReference, [4]int{1, 2, MaxRows, 4}},
+		{"1:1", RowReference, [4]int{1, 1, 1, MaxColumns}},
+		{"Sheet1!$3:$5", RowReference, [4]int{3, 1, 5, MaxColumns}},
+		{"'My Sheet'!XFD:XFD", ColumnReference, [4]int{1, MaxColumns, MaxRows, MaxColumns}},
+	}
+	for _, testCase := range testCases {
+		tokens, err := Tokenize(testCase.formula)
+		if err != nil || len(tokens) != 1 {
+			t.Errorf("%s should be one token, but %v %v", testCase.formula, tokens, err)
+			continue
+		}
+		reference, ok := tokens[0].Reference()
+		if !ok {
+			t.Errorf("%s should be a reference", testCase.formula)
+			continue
+		}
+		actual := [4]int{reference.FirstRow, reference.FirstCol, reference.LastRow, reference.LastCol}
+		if reference.Kind != testCase.kind || actual != testCase.expected {
+			t.Errorf("%s should be %s %v, but %s %v", testCase.formula, testCase.kind, testCase.expected, reference.Kind, actual)
+		}
+	}
+	tokens, _ := Tokenize("Total")
+	if _, ok := tokens[0].Reference(); ok {
+		t.Errorf("Name token should not be a reference")
+	}
+	tokens, _ = Tokenize("A1")
+	tokens[0].Text = "B2"
+	if reference, _ := tokens[0].Reference(); reference.FirstRow != 2 || reference.FirstCol != 2 {
+		t.Errorf("edited token should be B2, but %s", reference)
+	}
+	tokens[0].Text = "Total"
+	if _, ok := tokens[0].Reference(); ok {
+		t.Errorf("token edited to invalid text should not be a reference")
+	}
+}
+
+func TestReferenceParts(t *testing.T) {
+	tokens, _ := Tokenize("[1]Sheet1!$C5:A$1")
+	reference, _ := tokens[0].Reference()
+	if reference.Workbook != "1" || reference.Sheet != "Sheet1" || reference.FirstRow != 1 || reference.FirstCol != 1 || reference.LastRow != 5 || reference.LastCol != 3 {
+		t.Errorf("reference should be [1]Sheet1 A1:C5, but %#v", reference)
+	}
+	if !reference.FirstRowAbs || reference.FirstColAbs || reference.LastRowAbs || !reference.LastColAbs {
+		t.Errorf("absolute flags should follow the swapped bounds, but %#v", reference)
+	}
+	if actual := reference.String(); actual != "[1]Sheet1!A$1:$C5" {
+		t.Errorf("String() should be [1]Sheet1!A$1:$C5, but %s", actual)
+	}
+	for _, formula := range []string{"$B:$D", "Sheet1:Sheet3!$3:5", "'My Sheet'!C4", "A1:B2"} {
+		tokens, _ := Tokenize(formula)
+		if reference, _ := tokens[0].Reference(); reference.String() != formula {
+			t.Errorf("String() should be %s, but %s", formula, reference.String())
+		}
+	}
+}
+
+func TestReferenceHelpers(t *testing.T) {
+	reference := func(formula string) Reference {
+		tokens, _ := Tokenize(formula)
+		result, _ := tokens[0].Reference()
+		return result
+	}
+	area := reference("B2:D5")
+	if area.Rows() != 4 || area.Cols() != 3 || area.Size() != 12 {
+		t.Errorf("B2:D5 should be 4x3, but %dx%d (%d)", area.Rows(), area.Cols(), area.Size())
+	}
+	if !area.Contains(5, 4) || area.Contains(1, 2) {
+		t.Errorf("B2:D5 should contain D5 but not B1")
+	}
+	if !reference("A:D").ContainsReference(area) || area.ContainsReference(reference("A:D")) {
+		t.Errorf("A:D should contain B2:D5")
+	}
+	if reference("Sheet1!A:D").ContainsReference(area) || !reference("sheet1!1:5").ContainsReference(reference("Sheet1!B2:D5")) {
+		t.Errorf("sheet names should be compared")
+	}
+	testCases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"A1:C3", "B2:D4", "B2:C3"},
+		{"A:C", "B:D", "B:C"},
+		{"2:3", "A:A", "A2:A3"},
+		{"A1:C3", "C3:D4", "C3"},
+		{"A1:B2", "C3:D4", ""},
+		{"Sheet1!A1:B2", "Sheet2!A1:B2", ""},
+	}
+	for _, testCase := range testCases {
+		result, ok := reference(testCase.a).Intersect(reference(testCase.b))
+		if actual := result.String(); ok != (testCase.expected != "") || (ok && actual != testCase.expected) {
+			t.Errorf("%s %s should be %s, but %s (%v)", testCase.a, testCase.b, testCase.expected, actual, ok)
+		}
+	}
+	var cells []string
+	area.Each(func(row, col int) bool {
+		cells = append(cells, cellAddress{Row: row, Col: col}.String())
+		return len(cells) < 4
+	})
+	if strings.Join(cells, ",") != "B2,C2,D2,B3" {
+		t.Errorf("Each() should visit B2,C2,D2,B3, but %v", cells)
+	}
+}
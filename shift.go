@@ -0,0 +1,77 @@
+package xlsxformula
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Shift returns a copy of node moved by dRow rows and dCol columns, like Excel does when
+// a formula is copied from a cell to another. Only the parts of Range tokens without $ are
+// moved. A reference pushed off the sheet becomes #REF! error. Sheet names, names and
+// strings are not changed.
+func Shift(node *Node, dRow, dCol int) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("node is nil")
+	}
+	return transformTokens(node, func(token *Token) (*Token, error) {
+		if token.Type != Range {
+			return token, nil
+		}
+		parts := strings.Split(token.Text, ":")
+		for i, part := range parts {
+			address, ok := parseReferencePart(part)
+			if !ok {
+				return nil, fmt.Errorf("Invalid range '%s' at %d:%d", token.Text, token.Line, token.Col)
+			}
+			if address.Row != 0 && !address.RowAbs {
+				address.Row += dRow
+				if address.Row < 1 || address.Row > MaxRows {
+					return refErrorToken(token), nil
+				}
+			}
+			if address.Col != 0 && !address.ColAbs {
+				address.Col += dCol
+				if address.Col < 1 || address.Col > MaxColumns {
+					return refErrorToken(token), nil
+				}
+			}
+			parts[i] = address.String()
+		}
+		result := *token
+		result.Text = strings.Join(parts, ":")
+		return &result, nil
+	})
+}
+
+// refErrorToken returns #REF! error token that replaces the deleted reference. The sheet name is kept like Sheet1!#REF!.
+func refErrorToken(token *Token) *Token {
+	result := *token
+	result.Type = Error
+	result.Text = string(RefError)
+	return &result
+}
+
+// transformTokens copies node and replaces the tokens of SingleToken nodes (including array elements) by f.
+func transformTokens(node *Node, f func(token *Token) (*Token, error)) (*Node, error) {
+	result := *node
+	if node.Type == SingleToken {
+		token, err := f(node.Token)
+		if err != nil {
+			return nil, err
+		}
+		result.Token = token
+		return &result, nil
+	}
+	if node.Children != nil {
+		result.Children = make([]*Node, len(node.Children))
+		for i, child := range node.Children {
+			converted, err := transformTokens(child, f)
+			if err != nil {
+				return nil, err
+			}
+			result.Children[i] = converted
+		}
+	}
+	return &result, nil
+}
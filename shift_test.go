@@ -0,0 +1,49 @@
+package xlsxformula
+
+import (
+	"testing"
+)
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		formula  string
+		dRow     int
+		dCol     int
+		expected string
+	}{
+		{"A2+$B$1", 1, 0, "A3+$B$1"},
+		{"A2+$B$1", 0, 2, "C2+$B$1"},
+		{"SUM($A1:B$2)*A$1", 3, 1, "SUM($A4:C$2)*B$1"},
+		{"Sheet2!A1+'Q1 Data'!B2", 1, 1, "Sheet2!B2+'Q1 Data'!C3"},
+		{`Rate*A1&"A1"`, 1, 0, `Rate*A2&"A1"`},
+		{"A1+1", -1, 0, "#REF!+1"},
+		{"Sheet2!A1", 0, -1, "Sheet2!#REF!"},
+		{"$A$1+XFD1", 0, 1, "$A$1+#REF!"},
+		{"SUM(A1:B1048576)", 1, 0, "SUM(#REF!)"},
+		{"{1,2}*A1", 1, 0, "{1,2}*A2"},
+		{"A1", -1, -1, "#REF!"},
+	}
+	for _, test := range tests {
+		node, err := ParseWithOptions(test.formula, ParseOptions{})
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", test.formula, err)
+			continue
+		}
+		shifted, err := Shift(node, test.dRow, test.dCol)
+		if err != nil {
+			t.Errorf("err of Shift(%s) should be nil, but %v", test.formula, err)
+		} else if formatted := Format(shifted, FormatOptions{}); formatted != test.expected {
+			t.Errorf("Shift(%s, %d, %d) should be %s, but %s", test.formula, test.dRow, test.dCol, test.expected, formatted)
+		}
+	}
+}
+
+func TestShiftKeepsOriginal(t *testing.T) {
+	node, _ := Parse("A1+B2")
+	if _, err := Shift(node, 5, 5); err != nil {
+		t.Errorf("err should be nil, but %v", err)
+	}
+	if formatted := Format(node, FormatOptions{}); formatted != "A1+B2" {
+		t.Errorf("original node should not be modified, but %s", formatted)
+	}
+}
@@ -0,0 +1,164 @@
+package xlsxformula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StructuredReference is a parsed table reference like Table1[[#This Row],[Amount]].
+type StructuredReference struct {
+	Table       string   // table name. It is empty for [@Amount] that is written in the table
+	Items       []string // item specifiers: "#All", "#Data", "#Headers", "#Totals", "#This Row" ("@" becomes "#This Row")
+	FirstColumn string   // column name without escape characters. It is empty if the whole table is referred
+	LastColumn  string   // last column of column range like [[Col1]:[Col3]]. It is empty for a single column
+}
+
+var structuredItems []string = []string{"#All", "#Data", "#Headers", "#Totals", "#This Row"}
+
+// readStructuredReference reads the brackets of a structured reference starts at source[index].
+// It returns the index after the closing bracket.
+func readStructuredReference(source []rune, index int, table string) (*StructuredReference, int, error) {
+	ref := &StructuredReference{Table: table}
+	i := index + 1
+	nested := skipSpaces(source, i)
+	switch {
+	case i < len(source) && source[i] == '@':
+		// Table1[@Amount], Table1[@[Unit Price]]
+		// Table1[@[Col1]:[Col3]]
+		ref.Items = []string{"#This Row"}
+		i = skipSpaces(source, i+1)
+		if i < len(source) && source[i] == '[' {
+			column, _, next, err := readBracket(source, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			ref.FirstColumn = column
+			i, err = readLastColumn(source, next, index, ref)
+			if err != nil {
+				return nil, 0, err
+			}
+			if i >= len(source) || source[i] != ']' {
+				return nil, 0, fmt.Errorf("closing bracket of structured reference is missing: %s", string(source[index:]))
+			}
+			return ref, i + 1, nil
+		}
+		column, _, next, err := readBracket(source, index)
+		if err != nil {
+			return nil, 0, err
+		}
+		ref.FirstColumn = column[1:]
+		return ref, next, nil
+	case nested < len(source) && source[nested] == '[':
+		// Table1[[#Headers],[Col1]:[Col3]], Table1[ [Col1] ]
+		for {
+			i = skipSpaces(source, i)
+			if i >= len(source) || source[i] != '[' {
+				return nil, 0, fmt.Errorf("'[' is expected in structured reference: %s", string(source[index:]))
+			}
+			text, item, next, err := readBracket(source, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			i = skipSpaces(source, next)
+			if item {
+				item, err := structuredItem(text)
+				if err != nil {
+					return nil, 0, err
+				}
+				ref.Items = append(ref.Items, item)
+			} else {
+				if ref.FirstColumn != "" {
+					return nil, 0, fmt.Errorf("structured reference can have only one column or column range: %s", string(source[index:]))
+				}
+				ref.FirstColumn = text
+				i, err = readLastColumn(source, i, index, ref)
+				if err != nil {
+					return nil, 0, err
+				}
+			}
+			if i < len(source) && source[i] == ',' {
+				i++
+				continue
+			}
+			if i < len(source) && source[i] == ']' {
+				return ref, i + 1, nil
+			}
+			return nil, 0, fmt.Errorf("closing bracket of structured reference is missing: %s", string(source[index:]))
+		}
+	}
+	// Table1[Amount], Table1[#All], Table1[]
+	text, item, next, err := readBracket(source, index)
+	if err != nil {
+		return nil, 0, err
+	}
+	if item {
+		item, err := structuredItem(text)
+		if err != nil {
+			return nil, 0, err
+		}
+		ref.Items = []string{item}
+	} else {
+		ref.FirstColumn = text
+	}
+	return ref, next, nil
+}
+
+// readLastColumn reads ":[Col3]" of a column range starts at source[i] if it exists.
+// It returns the index after it and spaces. index is the start of the structured reference for error messages.
+func readLastColumn(source []rune, i, index int, ref *StructuredReference) (int, error) {
+	i = skipSpaces(source, i)
+	if i >= len(source) || source[i] != ':' {
+		return i, nil
+	}
+	i = skipSpaces(source, i+1)
+	if i >= len(source) || source[i] != '[' {
+		return 0, fmt.Errorf("last column is missing in structured reference: %s", string(source[index:]))
+	}
+	column, _, next, err := readBracket(source, i)
+	if err != nil {
+		return 0, err
+	}
+	ref.LastColumn = column
+	return skipSpaces(source, next), nil
+}
+
+// readBracket reads [...] starts at source[index] and returns the text in it.
+// ' escapes the next character like [Price '[USD']]. The bool result is true if the text
+// starts with unescaped '#' like [#All]; ['#Col] is a column named "#Col".
+func readBracket(source []rune, index int) (string, bool, int, error) {
+	var text []rune
+	item := false
+	for i := index + 1; i < len(source); i++ {
+		switch source[i] {
+		case '\'':
+			i++
+			if i == len(source) {
+				return "", false, 0, fmt.Errorf("escaped character is missing in structured reference: %s", string(source[index:]))
+			}
+		case '[':
+			return "", false, 0, fmt.Errorf("'[' should be escaped in structured reference: %s", string(source[index:]))
+		case ']':
+			return string(text), item, i + 1, nil
+		case '#':
+			item = item || i == index+1
+		}
+		text = append(text, source[i])
+	}
+	return "", false, 0, fmt.Errorf("closing bracket of structured reference is missing: %s", string(source[index:]))
+}
+
+func structuredItem(text string) (string, error) {
+	for _, item := range structuredItems {
+		if strings.EqualFold(item, text) {
+			return item, nil
+		}
+	}
+	return "", fmt.Errorf("unknown item specifier of structured reference: %s", text)
+}
+
+func skipSpaces(source []rune, index int) int {
+	for index < len(source) && source[index] == ' ' {
+		index++
+	}
+	return index
+}
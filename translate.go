@@ -0,0 +1,157 @@
+package xlsxformula
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Language is a language of function names in localized Excel.
+type Language string
+
+const (
+	English    Language = "en"
+	German     Language = "de"
+	French     Language = "fr"
+	Spanish    Language = "es"
+	Portuguese Language = "pt" // Brazilian Portuguese
+	Italian    Language = "it"
+	Dutch      Language = "nl"
+	Japanese   Language = "ja" // Japanese Excel uses the English names
+)
+
+// englishNames maps the localized function names to English names.
+var englishNames = map[Language]map[string]string{}
+
+func init() {
+	for language, names := range functionNames {
+		reverse := make(map[string]string, len(names))
+		for english, localized := range names {
+			reverse[localized] = english
+		}
+		englishNames[language] = reverse
+	}
+}
+
+// UnknownFunctionError is returned by TranslateFunctions when some function names are not
+// builtin functions of the language.
+type UnknownFunctionError struct {
+	Language Language
+	Names    []string
+}
+
+func (e *UnknownFunctionError) Error() string {
+	return fmt.Sprintf("unknown function names in %s: %s", e.Language, strings.Join(e.Names, ", "))
+}
+
+// TranslateFunctionName translates a function name from one language to another like "SUMME" into "SUM".
+// User defined functions are returned in upper case as they are. It returns an error if the name is
+// not a function of the language like "SUM" in German. The "_xlfn." prefix is kept.
+func TranslateFunctionName(name string, from, to Language) (string, error) {
+	if err := checkLanguages(from, to); err != nil {
+		return "", err
+	}
+	original, prefix := name, ""
+	if len(name) > 6 && strings.EqualFold(name[:6], "_xlfn.") {
+		prefix, name = name[:6], name[6:]
+	}
+	english, ok := englishName(name, from)
+	if !ok {
+		return "", &UnknownFunctionError{Language: from, Names: []string{original}}
+	}
+	if localized, found := functionNames[to][english]; found {
+		return prefix + localized, nil
+	}
+	return prefix + english, nil
+}
+
+func checkLanguages(languages ...Language) error {
+	for _, language := range languages {
+		if _, ok := functionNames[language]; !ok {
+			return fmt.Errorf("unsupported language: %s", language)
+		}
+	}
+	return nil
+}
+
+// englishName returns the English name of the function in the language.
+func englishName(name string, language Language) (string, bool) {
+	name = strings.ToUpper(name)
+	if english, ok := englishNames[language][name]; ok {
+		return english, true
+	}
+	spec := lookupFunction(name)
+	if spec == nil {
+		return "", false
+	}
+	// English names are used as they are only in English Excel
+	return name, len(functionNames[language]) == 0 || spec.user
+}
+
+// TranslateFunctions returns a copy of node whose function names and TRUE/FALSE are translated from one
+// language to another. Formulas in localized Excel can be normalized to English before Evaluate() or BuildGraph()
+// and translated back for display. Localized TRUE/FALSE like WAHR are Name tokens and they become Bool tokens
+// in English. Separators are not changed; use Separators of ParseOptions and FormatOptions.
+// If some names are not functions of the language, they are left as they are in the returned node and
+// *UnknownFunctionError that lists them is returned.
+func TranslateFunctions(node *Node, from, to Language) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("node is nil")
+	}
+	if err := checkLanguages(from, to); err != nil {
+		return nil, err
+	}
+	unknown := &UnknownFunctionError{Language: from}
+	result := translateFunctions(node, from, to, unknown)
+	if len(unknown.Names) > 0 {
+		return result, unknown
+	}
+	return result, nil
+}
+
+func translateFunctions(node *Node, from, to Language, unknown *UnknownFunctionError) *Node {
+	result := *node
+	if node.Type == Function && node.Token != nil {
+		name, err := TranslateFunctionName(node.Token.Text, from, to)
+		if err != nil {
+			unknown.Names = append(unknown.Names, node.Token.Text)
+		} else {
+			token := *node.Token
+			token.Text = name
+			result.Token = &token
+		}
+	}
+	if node.Type == SingleToken && node.Token != nil {
+		if token, ok := translateBool(node.Token, from, to); ok {
+			result.Token = token
+		}
+	}
+	if node.Children != nil {
+		result.Children = make([]*Node, len(node.Children))
+		for i, child := range node.Children {
+			result.Children[i] = translateFunctions(child, from, to, unknown)
+		}
+	}
+	return &result
+}
+
+// translateBool translates Bool tokens of English and Name tokens of localized TRUE/FALSE.
+func translateBool(token *Token, from, to Language) (*Token, bool) {
+	var english string
+	switch {
+	case token.Type == Bool && len(functionNames[from]) == 0:
+		english = token.Normalized()
+	case token.Type == Name && token.Sheet == "" && token.Workbook == "":
+		english = englishNames[from][token.Normalized()]
+	}
+	if english != "TRUE" && english != "FALSE" {
+		return nil, false
+	}
+	result := *token
+	if localized, ok := functionNames[to][english]; ok {
+		result.Type, result.Text = Name, localized
+	} else {
+		result.Type, result.Text = Bool, english
+	}
+	return &result, true
+}
@@ -0,0 +1,436 @@
+package xlsxformula
+
+// functionNames maps English names of all builtin functions to the localized names.
+// English and Japanese Excel use the English names.
+var functionNames = map[Language]map[string]string{
+	English:  {},
+	Japanese: {},
+	German: {
+		"ABS": "ABS", "ACOS": "ARCCOS", "ACOSH": "ARCCOSHYP", "ACOT": "ARCCOT", "ACOTH": "ARCCOTHYP",
+		"ADDRESS": "ADRESSE", "AND": "UND", "ARABIC": "ARABISCH", "AREAS": "BEREICHE", "ASIN": "ARCSIN",
+		"ASINH": "ARCSINHYP", "ATAN": "ARCTAN", "ATAN2": "ARCTAN2", "ATANH": "ARCTANHYP", "AVEDEV": "MITTELABW",
+		"AVERAGE": "MITTELWERT", "AVERAGEA": "MITTELWERTA", "AVERAGEIF": "MITTELWERTWENN",
+		"AVERAGEIFS": "MITTELWERTWENNS", "BASE": "BASIS", "BIN2DEC": "BININDEZ", "BIN2HEX": "BININHEX",
+		"BIN2OCT": "BININOKT", "BINOM.DIST": "BINOM.VERT", "BINOMDIST": "BINOMVERT", "BITAND": "BITUND",
+		"BITLSHIFT": "BITLVERSCHIEB", "BITOR": "BITODER", "BITRSHIFT": "BITRVERSCHIEB", "BITXOR": "BITXODER",
+		"CEILING": "OBERGRENZE", "CEILING.MATH": "OBERGRENZE.MATHEMATIK", "CEILING.PRECISE": "OBERGRENZE.GENAU",
+		"CHAR": "ZEICHEN", "CHOOSE": "WAHL", "CLEAN": "SÄUBERN", "CODE": "CODE", "COLUMN": "SPALTE",
+		"COLUMNS": "SPALTEN", "COMBIN": "KOMBINATIONEN", "COMBINA": "KOMBINATIONEN2", "CONCAT": "TEXTKETTE",
+		"CONCATENATE": "VERKETTEN", "CONFIDENCE": "KONFIDENZ", "CONFIDENCE.NORM": "KONFIDENZ.NORM",
+		"CORREL": "KORREL", "COS": "COS", "COSH": "COSHYP", "COT": "COT", "COTH": "COTHYP", "COUNT": "ANZAHL",
+		"COUNTA": "ANZAHL2", "COUNTBLANK": "ANZAHLLEEREZELLEN", "COUNTIF": "ZÄHLENWENN", "COUNTIFS": "ZÄHLENWENNS",
+		"COVAR": "KOVAR", "COVARIANCE.P": "KOVARIANZ.P", "COVARIANCE.S": "KOVARIANZ.S", "CSC": "COSEC",
+		"CSCH": "COSECHYP", "DATE": "DATUM", "DATEDIF": "DATEDIF", "DATEVALUE": "DATWERT", "DAY": "TAG",
+		"DAYS": "TAGE", "DAYS360": "TAGE360", "DDB": "GDA", "DEC2BIN": "DEZINBIN", "DEC2HEX": "DEZINHEX",
+		"DEC2OCT": "DEZINOKT", "DECIMAL": "DEZIMAL", "DEGREES": "GRAD", "DELTA": "DELTA", "DEVSQ": "SUMQUADABW",
+		"DOLLAR": "DM", "EDATE": "EDATUM", "EFFECT": "EFFEKTIV", "EOMONTH": "MONATSENDE",
+		"ERROR.TYPE": "FEHLER.TYP", "EVEN": "GERADE", "EXACT": "IDENTISCH", "EXP": "EXP",
+		"EXPON.DIST": "EXPON.VERT", "EXPONDIST": "EXPONVERT", "FACT": "FAKULTÄT", "FACTDOUBLE": "ZWEIFAKULTÄT",
+		"FALSE": "FALSCH", "FILTER": "FILTER", "FIND": "FINDEN", "FISHER": "FISHER", "FISHERINV": "FISHERINV",
+		"FIXED": "FEST", "FLOOR": "UNTERGRENZE", "FLOOR.MATH": "UNTERGRENZE.MATHEMATIK",
+		"FLOOR.PRECISE": "UNTERGRENZE.GENAU", "FORECAST": "PROGNOSE", "FORECAST.LINEAR": "PROGNOSE.LINEAR",
+		"FV": "ZW", "FVSCHEDULE": "ZW2", "GAMMA": "GAMMA", "GAMMALN": "GAMMALN",
+		"GAMMALN.PRECISE": "GAMMALN.GENAU", "GAUSS": "GAUSS", "GCD": "GGT", "GEOMEAN": "GEOMITTEL",
+		"GESTEP": "GGANZZAHL", "HARMEAN": "HARMITTEL", "HEX2BIN": "HEXINBIN", "HEX2DEC": "HEXINDEZ",
+		"HEX2OCT": "HEXINOKT", "HLOOKUP": "WVERWEIS", "HOUR": "STUNDE", "IF": "WENN", "IFERROR": "WENNFEHLER",
+		"IFNA": "WENNNV", "IFS": "WENNS", "INDEX": "INDEX", "INDIRECT": "INDIREKT", "INT": "GANZZAHL",
+		"INTERCEPT": "ACHSENABSCHNITT", "IPMT": "ZINSZ", "IRR": "IKV", "ISBLANK": "ISTLEER", "ISERR": "ISTFEHL",
+		"ISERROR": "ISTFEHLER", "ISEVEN": "ISTGERADE", "ISLOGICAL": "ISTLOG", "ISNA": "ISTNV",
+		"ISNONTEXT": "ISTKTEXT", "ISNUMBER": "ISTZAHL", "ISO.CEILING": "ISO.OBERGRENZE", "ISODD": "ISTUNGERADE",
+		"ISOWEEKNUM": "ISOKALENDERWOCHE", "ISREF": "ISTBEZUG", "ISTEXT": "ISTTEXT", "KURT": "KURT",
+		"LARGE": "KGRÖSSTE", "LCM": "KGV", "LEFT": "LINKS", "LEN": "LÄNGE", "LN": "LN", "LOG": "LOG",
+		"LOG10": "LOG10", "LOOKUP": "VERWEIS", "LOWER": "KLEIN", "MATCH": "VERGLEICH", "MAX": "MAX",
+		"MAXA": "MAXA", "MAXIFS": "MAXWENNS", "MEDIAN": "MEDIAN", "MID": "TEIL", "MIN": "MIN", "MINA": "MINA",
+		"MINIFS": "MINWENNS", "MINUTE": "MINUTE", "MOD": "REST", "MODE": "MODALWERT", "MODE.SNGL": "MODUS.EINF",
+		"MONTH": "MONAT", "MROUND": "VRUNDEN", "MULTINOMIAL": "POLYNOMIAL", "N": "N", "NA": "NV",
+		"NETWORKDAYS": "NETTOARBEITSTAGE", "NOMINAL": "NOMINAL", "NORM.DIST": "NORM.VERT", "NORM.INV": "NORM.INV",
+		"NORM.S.DIST": "NORM.S.VERT", "NORM.S.INV": "NORM.S.INV", "NORMDIST": "NORMVERT", "NORMINV": "NORMINV",
+		"NORMSDIST": "STANDNORMVERT", "NORMSINV": "STANDNORMINV", "NOT": "NICHT", "NOW": "JETZT", "NPER": "ZZR",
+		"NPV": "NBW", "NUMBERVALUE": "ZAHLENWERT", "OCT2BIN": "OKTINBIN", "OCT2DEC": "OKTINDEZ",
+		"OCT2HEX": "OKTINHEX", "ODD": "UNGERADE", "OFFSET": "BEREICH.VERSCHIEBEN", "OR": "ODER",
+		"PDURATION": "PDURATION", "PEARSON": "PEARSON", "PERCENTILE": "QUANTIL", "PERCENTILE.EXC": "QUANTIL.EXKL",
+		"PERCENTILE.INC": "QUANTIL.INKL", "PERCENTRANK": "QUANTILSRANG", "PERCENTRANK.INC": "QUANTILSRANG.INKL",
+		"PERMUT": "VARIATIONEN", "PERMUTATIONA": "VARIATIONEN2", "PHI": "PHI", "PI": "PI", "PMT": "RMZ",
+		"POISSON": "POISSON", "POISSON.DIST": "POISSON.VERT", "POWER": "POTENZ", "PPMT": "KAPZ",
+		"PRODUCT": "PRODUKT", "PROPER": "GROSS2", "PV": "BW", "QUARTILE": "QUARTILE",
+		"QUARTILE.EXC": "QUARTILE.EXKL", "QUARTILE.INC": "QUARTILE.INKL", "QUOTIENT": "QUOTIENT",
+		"RADIANS": "BOGENMASS", "RAND": "ZUFALLSZAHL", "RANDBETWEEN": "ZUFALLSBEREICH", "RANK": "RANG",
+		"RANK.AVG": "RANG.MITTELW", "RANK.EQ": "RANG.GLEICH", "RATE": "ZINS", "REPLACE": "ERSETZEN",
+		"REPT": "WIEDERHOLEN", "RIGHT": "RECHTS", "ROMAN": "RÖMISCH", "ROUND": "RUNDEN", "ROUNDDOWN": "ABRUNDEN",
+		"ROUNDUP": "AUFRUNDEN", "ROW": "ZEILE", "ROWS": "ZEILEN", "RRI": "ZSATZINVEST", "RSQ": "BESTIMMTHEITSMASS",
+		"SEARCH": "SUCHEN", "SEC": "SEC", "SECH": "SECHYP", "SECOND": "SEKUNDE", "SEQUENCE": "SEQUENZ",
+		"SERIESSUM": "POTENZREIHE", "SIGN": "VORZEICHEN", "SIN": "SIN", "SINH": "SINHYP", "SKEW": "SCHIEFE",
+		"SLN": "LIA", "SLOPE": "STEIGUNG", "SMALL": "KKLEINSTE", "SORT": "SORTIEREN", "SQRT": "WURZEL",
+		"SQRTPI": "WURZELPI", "STANDARDIZE": "STANDARDISIERUNG", "STDEV": "STABW", "STDEV.P": "STABW.N",
+		"STDEV.S": "STABW.S", "STDEVA": "STABWA", "STDEVP": "STABWN", "STDEVPA": "STABWNA", "STEYX": "STFEHLERYX",
+		"SUBSTITUTE": "WECHSELN", "SUBTOTAL": "TEILERGEBNIS", "SUM": "SUMME", "SUMIF": "SUMMEWENN",
+		"SUMIFS": "SUMMEWENNS", "SUMPRODUCT": "SUMMENPRODUKT", "SUMSQ": "QUADRATESUMME", "SUMX2MY2": "SUMMEX2MY2",
+		"SUMX2PY2": "SUMMEX2PY2", "SUMXMY2": "SUMMEXMY2", "SWITCH": "ERSTERWERT", "SYD": "DIA", "T": "T",
+		"TAN": "TAN", "TANH": "TANHYP", "TEXT": "TEXT", "TEXTAFTER": "TEXTNACH", "TEXTBEFORE": "TEXTVOR",
+		"TEXTJOIN": "TEXTVERKETTEN", "TIME": "ZEIT", "TIMEVALUE": "ZEITWERT", "TODAY": "HEUTE",
+		"TRANSPOSE": "MTRANS", "TRIM": "GLÄTTEN", "TRIMMEAN": "GESTUTZTMITTEL", "TRUE": "WAHR", "TRUNC": "KÜRZEN",
+		"TYPE": "TYP", "UNICHAR": "UNIZEICHEN", "UNICODE": "UNICODE", "UNIQUE": "EINDEUTIG", "UPPER": "GROSS",
+		"VALUE": "WERT", "VAR": "VARIANZ", "VAR.P": "VAR.P", "VAR.S": "VAR.S", "VARA": "VARIANZA",
+		"VARP": "VARIANZEN", "VARPA": "VARIANZENA", "VLOOKUP": "SVERWEIS", "WEEKDAY": "WOCHENTAG",
+		"WEEKNUM": "KALENDERWOCHE", "WORKDAY": "ARBEITSTAG", "XLOOKUP": "XVERWEIS", "XMATCH": "XVERGLEICH",
+		"XOR": "XODER", "YEAR": "JAHR", "YEARFRAC": "BRTEILJAHRE",
+	},
+	French: {
+		"ABS": "ABS", "ACOS": "ACOS", "ACOSH": "ACOSH", "ACOT": "ACOT", "ACOTH": "ACOTH", "ADDRESS": "ADRESSE",
+		"AND": "ET", "ARABIC": "CHIFFRE.ARABE", "AREAS": "ZONES", "ASIN": "ASIN", "ASINH": "ASINH", "ATAN": "ATAN",
+		"ATAN2": "ATAN2", "ATANH": "ATANH", "AVEDEV": "ECART.MOYEN", "AVERAGE": "MOYENNE", "AVERAGEA": "AVERAGEA",
+		"AVERAGEIF": "MOYENNE.SI", "AVERAGEIFS": "MOYENNE.SI.ENS", "BASE": "BASE", "BIN2DEC": "BINDEC",
+		"BIN2HEX": "BINHEX", "BIN2OCT": "BINOCT", "BINOM.DIST": "LOI.BINOMIALE.N", "BINOMDIST": "LOI.BINOMIALE",
+		"BITAND": "BITET", "BITLSHIFT": "BITDECALG", "BITOR": "BITOU", "BITRSHIFT": "BITDECALD",
+		"BITXOR": "BITOUEXCLUSIF", "CEILING": "PLAFOND", "CEILING.MATH": "PLAFOND.MATH",
+		"CEILING.PRECISE": "PLAFOND.PRECIS", "CHAR": "CAR", "CHOOSE": "CHOISIR", "CLEAN": "EPURAGE",
+		"CODE": "CODE", "COLUMN": "COLONNE", "COLUMNS": "COLONNES", "COMBIN": "COMBIN", "COMBINA": "COMBINA",
+		"CONCAT": "CONCAT", "CONCATENATE": "CONCATENER", "CONFIDENCE": "INTERVALLE.CONFIANCE",
+		"CONFIDENCE.NORM": "INTERVALLE.CONFIANCE.NORMAL", "CORREL": "COEFFICIENT.CORRELATION", "COS": "COS",
+		"COSH": "COSH", "COT": "COT", "COTH": "COTH", "COUNT": "NB", "COUNTA": "NBVAL", "COUNTBLANK": "NB.VIDE",
+		"COUNTIF": "NB.SI", "COUNTIFS": "NB.SI.ENS", "COVAR": "COVARIANCE", "COVARIANCE.P": "COVARIANCE.PEARSON",
+		"COVARIANCE.S": "COVARIANCE.STANDARD", "CSC": "CSC", "CSCH": "CSCH", "DATE": "DATE", "DATEDIF": "DATEDIF",
+		"DATEVALUE": "DATEVAL", "DAY": "JOUR", "DAYS": "JOURS", "DAYS360": "JOURS360", "DDB": "DDB",
+		"DEC2BIN": "DECBIN", "DEC2HEX": "DECHEX", "DEC2OCT": "DECOCT", "DECIMAL": "DECIMAL", "DEGREES": "DEGRES",
+		"DELTA": "DELTA", "DEVSQ": "SOMME.CARRES.ECARTS", "DOLLAR": "DEVISE", "EDATE": "MOIS.DECALER",
+		"EFFECT": "TAUX.EFFECTIF", "EOMONTH": "FIN.MOIS", "ERROR.TYPE": "TYPE.ERREUR", "EVEN": "PAIR",
+		"EXACT": "EXACT", "EXP": "EXP", "EXPON.DIST": "LOI.EXPONENTIELLE.N", "EXPONDIST": "LOI.EXPONENTIELLE",
+		"FACT": "FACT", "FACTDOUBLE": "FACTDOUBLE", "FALSE": "FAUX", "FILTER": "FILTRE", "FIND": "TROUVE",
+		"FISHER": "FISHER", "FISHERINV": "FISHER.INVERSE", "FIXED": "CTXT", "FLOOR": "PLANCHER",
+		"FLOOR.MATH": "PLANCHER.MATH", "FLOOR.PRECISE": "PLANCHER.PRECIS", "FORECAST": "PREVISION",
+		"FORECAST.LINEAR": "PREVISION.LINEAIRE", "FV": "VC", "FVSCHEDULE": "VC.PAIEMENTS", "GAMMA": "GAMMA",
+		"GAMMALN": "LNGAMMA", "GAMMALN.PRECISE": "LNGAMMA.PRECIS", "GAUSS": "GAUSS", "GCD": "PGCD",
+		"GEOMEAN": "MOYENNE.GEOMETRIQUE", "GESTEP": "SUP.SEUIL", "HARMEAN": "MOYENNE.HARMONIQUE",
+		"HEX2BIN": "HEXBIN", "HEX2DEC": "HEXDEC", "HEX2OCT": "HEXOCT", "HLOOKUP": "RECHERCHEH", "HOUR": "HEURE",
+		"IF": "SI", "IFERROR": "SIERREUR", "IFNA": "SI.NON.DISP", "IFS": "SI.CONDITIONS", "INDEX": "INDEX",
+		"INDIRECT": "INDIRECT", "INT": "ENT", "INTERCEPT": "ORDONNEE.ORIGINE", "IPMT": "INTPER", "IRR": "TRI",
+		"ISBLANK": "ESTVIDE", "ISERR": "ESTERR", "ISERROR": "ESTERREUR", "ISEVEN": "EST.PAIR",
+		"ISLOGICAL": "ESTLOGIQUE", "ISNA": "ESTNA", "ISNONTEXT": "ESTNONTEXTE", "ISNUMBER": "ESTNUM",
+		"ISO.CEILING": "ISO.PLAFOND", "ISODD": "EST.IMPAIR", "ISOWEEKNUM": "NO.SEMAINE.ISO", "ISREF": "ESTREF",
+		"ISTEXT": "ESTTEXTE", "KURT": "KURTOSIS", "LARGE": "GRANDE.VALEUR", "LCM": "PPCM", "LEFT": "GAUCHE",
+		"LEN": "NBCAR", "LN": "LN", "LOG": "LOG", "LOG10": "LOG10", "LOOKUP": "RECHERCHE", "LOWER": "MINUSCULE",
+		"MATCH": "EQUIV", "MAX": "MAX", "MAXA": "MAXA", "MAXIFS": "MAX.SI.ENS", "MEDIAN": "MEDIANE", "MID": "STXT",
+		"MIN": "MIN", "MINA": "MINA", "MINIFS": "MIN.SI.ENS", "MINUTE": "MINUTE", "MOD": "MOD", "MODE": "MODE",
+		"MODE.SNGL": "MODE.SIMPLE", "MONTH": "MOIS", "MROUND": "ARRONDI.AU.MULTIPLE",
+		"MULTINOMIAL": "MULTINOMIALE", "N": "N", "NA": "NA", "NETWORKDAYS": "NB.JOURS.OUVRES",
+		"NOMINAL": "TAUX.NOMINAL", "NORM.DIST": "LOI.NORMALE.N", "NORM.INV": "LOI.NORMALE.INVERSE.N",
+		"NORM.S.DIST": "LOI.NORMALE.STANDARD.N", "NORM.S.INV": "LOI.NORMALE.STANDARD.INVERSE.N",
+		"NORMDIST": "LOI.NORMALE", "NORMINV": "LOI.NORMALE.INVERSE", "NORMSDIST": "LOI.NORMALE.STANDARD",
+		"NORMSINV": "LOI.NORMALE.STANDARD.INVERSE", "NOT": "NON", "NOW": "MAINTENANT", "NPER": "NPM", "NPV": "VAN",
+		"NUMBERVALUE": "VALEURNOMBRE", "OCT2BIN": "OCTBIN", "OCT2DEC": "OCTDEC", "OCT2HEX": "OCTHEX",
+		"ODD": "IMPAIR", "OFFSET": "DECALER", "OR": "OU", "PDURATION": "PDUREE", "PEARSON": "PEARSON",
+		"PERCENTILE": "CENTILE", "PERCENTILE.EXC": "CENTILE.EXCLURE", "PERCENTILE.INC": "CENTILE.INCLURE",
+		"PERCENTRANK": "RANG.POURCENTAGE", "PERCENTRANK.INC": "RANG.POURCENTAGE.INCLURE", "PERMUT": "PERMUTATION",
+		"PERMUTATIONA": "PERMUTATIONA", "PHI": "PHI", "PI": "PI", "PMT": "VPM", "POISSON": "LOI.POISSON",
+		"POISSON.DIST": "LOI.POISSON.N", "POWER": "PUISSANCE", "PPMT": "PRINCPER", "PRODUCT": "PRODUIT",
+		"PROPER": "NOMPROPRE", "PV": "VA", "QUARTILE": "QUARTILE", "QUARTILE.EXC": "QUARTILE.EXCLURE",
+		"QUARTILE.INC": "QUARTILE.INCLURE", "QUOTIENT": "QUOTIENT", "RADIANS": "RADIANS", "RAND": "ALEA",
+		"RANDBETWEEN": "ALEA.ENTRE.BORNES", "RANK": "RANG", "RANK.AVG": "MOYENNE.RANG", "RANK.EQ": "EQUATION.RANG",
+		"RATE": "TAUX", "REPLACE": "REMPLACER", "REPT": "REPT", "RIGHT": "DROITE", "ROMAN": "ROMAIN",
+		"ROUND": "ARRONDI", "ROUNDDOWN": "ARRONDI.INF", "ROUNDUP": "ARRONDI.SUP", "ROW": "LIGNE", "ROWS": "LIGNES",
+		"RRI": "TAUX.INT.EQUIV", "RSQ": "COEFFICIENT.DETERMINATION", "SEARCH": "CHERCHE", "SEC": "SEC",
+		"SECH": "SECH", "SECOND": "SECONDE", "SEQUENCE": "SEQUENCE", "SERIESSUM": "SOMME.SERIES", "SIGN": "SIGNE",
+		"SIN": "SIN", "SINH": "SINH", "SKEW": "COEFFICIENT.ASYMETRIE", "SLN": "AMORLIN", "SLOPE": "PENTE",
+		"SMALL": "PETITE.VALEUR", "SORT": "TRIER", "SQRT": "RACINE", "SQRTPI": "RACINE.PI",
+		"STANDARDIZE": "CENTREE.REDUITE", "STDEV": "ECARTYPE", "STDEV.P": "ECARTYPE.PEARSON",
+		"STDEV.S": "ECARTYPE.STANDARD", "STDEVA": "STDEVA", "STDEVP": "ECARTYPEP", "STDEVPA": "STDEVPA",
+		"STEYX": "ERREUR.TYPE.XY", "SUBSTITUTE": "SUBSTITUE", "SUBTOTAL": "SOUS.TOTAL", "SUM": "SOMME",
+		"SUMIF": "SOMME.SI", "SUMIFS": "SOMME.SI.ENS", "SUMPRODUCT": "SOMMEPROD", "SUMSQ": "SOMME.CARRES",
+		"SUMX2MY2": "SOMME.X2MY2", "SUMX2PY2": "SOMME.X2PY2", "SUMXMY2": "SOMME.XMY2", "SWITCH": "SI.MULTIPLE",
+		"SYD": "SYD", "T": "T", "TAN": "TAN", "TANH": "TANH", "TEXT": "TEXTE", "TEXTAFTER": "TEXTE.APRES",
+		"TEXTBEFORE": "TEXTE.AVANT", "TEXTJOIN": "JOINDRE.TEXTE", "TIME": "TEMPS", "TIMEVALUE": "TEMPSVAL",
+		"TODAY": "AUJOURDHUI", "TRANSPOSE": "TRANSPOSE", "TRIM": "SUPPRESPACE", "TRIMMEAN": "MOYENNE.REDUITE",
+		"TRUE": "VRAI", "TRUNC": "TRONQUE", "TYPE": "TYPE", "UNICHAR": "UNICAR", "UNICODE": "UNICODE",
+		"UNIQUE": "UNIQUE", "UPPER": "MAJUSCULE", "VALUE": "CNUM", "VAR": "VAR", "VAR.P": "VAR.P.N",
+		"VAR.S": "VAR.S", "VARA": "VARA", "VARP": "VAR.P", "VARPA": "VARPA", "VLOOKUP": "RECHERCHEV",
+		"WEEKDAY": "JOURSEM", "WEEKNUM": "NO.SEMAINE", "WORKDAY": "SERIE.JOUR.OUVRE", "XLOOKUP": "RECHERCHEX",
+		"XMATCH": "EQUIVX", "XOR": "OUX", "YEAR": "ANNEE", "YEARFRAC": "FRACTION.ANNEE",
+	},
+	Spanish: {
+		"ABS": "ABS", "ACOS": "ACOS", "ACOSH": "ACOSH", "ACOT": "ACOT", "ACOTH": "ACOTH", "ADDRESS": "DIRECCION",
+		"AND": "Y", "ARABIC": "NUMERO.ARABE", "AREAS": "AREAS", "ASIN": "ASENO", "ASINH": "ASENOH", "ATAN": "ATAN",
+		"ATAN2": "ATAN2", "ATANH": "ATANH", "AVEDEV": "DESVPROM", "AVERAGE": "PROMEDIO", "AVERAGEA": "PROMEDIOA",
+		"AVERAGEIF": "PROMEDIO.SI", "AVERAGEIFS": "PROMEDIO.SI.CONJUNTO", "BASE": "BASE", "BIN2DEC": "BIN.A.DEC",
+		"BIN2HEX": "BIN.A.HEX", "BIN2OCT": "BIN.A.OCT", "BINOM.DIST": "DISTR.BINOM.N", "BINOMDIST": "DISTR.BINOM",
+		"BITAND": "BIT.Y", "BITLSHIFT": "BIT.DESPLIZQDA", "BITOR": "BIT.O", "BITRSHIFT": "BIT.DESPLDCHA",
+		"BITXOR": "BIT.XO", "CEILING": "MULTIPLO.SUPERIOR", "CEILING.MATH": "MULTIPLO.SUPERIOR.MAT",
+		"CEILING.PRECISE": "MULTIPLO.SUPERIOR.EXACTO", "CHAR": "CARACTER", "CHOOSE": "ELEGIR", "CLEAN": "LIMPIAR",
+		"CODE": "CODIGO", "COLUMN": "COLUMNA", "COLUMNS": "COLUMNAS", "COMBIN": "COMBINAT", "COMBINA": "COMBINA",
+		"CONCAT": "CONCAT", "CONCATENATE": "CONCATENAR", "CONFIDENCE": "INTERVALO.CONFIANZA",
+		"CONFIDENCE.NORM": "INTERVALO.CONFIANZA.NORM", "CORREL": "COEF.DE.CORREL", "COS": "COS", "COSH": "COSH",
+		"COT": "COT", "COTH": "COTH", "COUNT": "CONTAR", "COUNTA": "CONTARA", "COUNTBLANK": "CONTAR.BLANCO",
+		"COUNTIF": "CONTAR.SI", "COUNTIFS": "CONTAR.SI.CONJUNTO", "COVAR": "COVAR", "COVARIANCE.P": "COVARIANCE.P",
+		"COVARIANCE.S": "COVARIANZA.M", "CSC": "CSC", "CSCH": "CSCH", "DATE": "FECHA", "DATEDIF": "SIFECHA",
+		"DATEVALUE": "FECHANUMERO", "DAY": "DIA", "DAYS": "DIAS", "DAYS360": "DIAS360", "DDB": "DDB",
+		"DEC2BIN": "DEC.A.BIN", "DEC2HEX": "DEC.A.HEX", "DEC2OCT": "DEC.A.OCT", "DECIMAL": "CONV.DECIMAL",
+		"DEGREES": "GRADOS", "DELTA": "DELTA", "DEVSQ": "DESVIA2", "DOLLAR": "MONEDA", "EDATE": "FECHA.MES",
+		"EFFECT": "INT.EFECTIVO", "EOMONTH": "FIN.MES", "ERROR.TYPE": "TIPO.DE.ERROR", "EVEN": "REDONDEA.PAR",
+		"EXACT": "IGUAL", "EXP": "EXP", "EXPON.DIST": "DISTR.EXP.N", "EXPONDIST": "DISTR.EXP", "FACT": "FACT",
+		"FACTDOUBLE": "FACT.DOBLE", "FALSE": "FALSO", "FILTER": "FILTRAR", "FIND": "ENCONTRAR", "FISHER": "FISHER",
+		"FISHERINV": "PRUEBA.FISHER.INV", "FIXED": "DECIMAL", "FLOOR": "MULTIPLO.INFERIOR",
+		"FLOOR.MATH": "MULTIPLO.INFERIOR.MAT", "FLOOR.PRECISE": "MULTIPLO.INFERIOR.EXACTO",
+		"FORECAST": "PRONOSTICO", "FORECAST.LINEAR": "PRONOSTICO.LINEAL", "FV": "VF", "FVSCHEDULE": "VF.PLAN",
+		"GAMMA": "GAMMA", "GAMMALN": "GAMMA.LN", "GAMMALN.PRECISE": "GAMMA.LN.EXACTO", "GAUSS": "GAUSS",
+		"GCD": "M.C.D", "GEOMEAN": "MEDIA.GEOM", "GESTEP": "MAYOR.O.IGUAL", "HARMEAN": "MEDIA.ARMO",
+		"HEX2BIN": "HEX.A.BIN", "HEX2DEC": "HEX.A.DEC", "HEX2OCT": "HEX.A.OCT", "HLOOKUP": "BUSCARH",
+		"HOUR": "HORA", "IF": "SI", "IFERROR": "SI.ERROR", "IFNA": "SI.ND", "IFS": "SI.CONJUNTO",
+		"INDEX": "INDICE", "INDIRECT": "INDIRECTO", "INT": "ENTERO", "INTERCEPT": "INTERSECCION.EJE",
+		"IPMT": "PAGOINT", "IRR": "TIR", "ISBLANK": "ESBLANCO", "ISERR": "ESERR", "ISERROR": "ESERROR",
+		"ISEVEN": "ES.PAR", "ISLOGICAL": "ESLOGICO", "ISNA": "ESNOD", "ISNONTEXT": "ESNOTEXTO",
+		"ISNUMBER": "ESNUMERO", "ISO.CEILING": "MULTIPLO.SUPERIOR.ISO", "ISODD": "ES.IMPAR",
+		"ISOWEEKNUM": "ISO.NUM.DE.SEMANA", "ISREF": "ESREF", "ISTEXT": "ESTEXTO", "KURT": "CURTOSIS",
+		"LARGE": "K.ESIMO.MAYOR", "LCM": "M.C.M", "LEFT": "IZQUIERDA", "LEN": "LARGO", "LN": "LN", "LOG": "LOG",
+		"LOG10": "LOG10", "LOOKUP": "BUSCAR", "LOWER": "MINUSC", "MATCH": "COINCIDIR", "MAX": "MAX",
+		"MAXA": "MAXA", "MAXIFS": "MAX.SI.CONJUNTO", "MEDIAN": "MEDIANA", "MID": "EXTRAE", "MIN": "MIN",
+		"MINA": "MINA", "MINIFS": "MIN.SI.CONJUNTO", "MINUTE": "MINUTO", "MOD": "RESIDUO", "MODE": "MODA",
+		"MODE.SNGL": "MODA.UNO", "MONTH": "MES", "MROUND": "REDOND.MULT", "MULTINOMIAL": "MULTINOMIAL", "N": "N",
+		"NA": "NOD", "NETWORKDAYS": "DIAS.LAB", "NOMINAL": "TASA.NOMINAL", "NORM.DIST": "DISTR.NORM.N",
+		"NORM.INV": "INV.NORM", "NORM.S.DIST": "DISTR.NORM.ESTAND.N", "NORM.S.INV": "INV.NORM.ESTAND",
+		"NORMDIST": "DISTR.NORM", "NORMINV": "DISTR.NORM.INV", "NORMSDIST": "DISTR.NORM.ESTAND",
+		"NORMSINV": "DISTR.NORM.ESTAND.INV", "NOT": "NO", "NOW": "AHORA", "NPER": "NPER", "NPV": "VNA",
+		"NUMBERVALUE": "VALOR.NUMERO", "OCT2BIN": "OCT.A.BIN", "OCT2DEC": "OCT.A.DEC", "OCT2HEX": "OCT.A.HEX",
+		"ODD": "REDONDEA.IMPAR", "OFFSET": "DESREF", "OR": "O", "PDURATION": "P.DURACION", "PEARSON": "PEARSON",
+		"PERCENTILE": "PERCENTIL", "PERCENTILE.EXC": "PERCENTIL.EXC", "PERCENTILE.INC": "PERCENTIL.INC",
+		"PERCENTRANK": "RANGO.PERCENTIL", "PERCENTRANK.INC": "RANGO.PERCENTIL.INC", "PERMUT": "PERMUTACIONES",
+		"PERMUTATIONA": "PERMUTACIONES.A", "PHI": "FI", "PI": "PI", "PMT": "PAGO", "POISSON": "POISSON",
+		"POISSON.DIST": "POISSON.DIST", "POWER": "POTENCIA", "PPMT": "PAGOPRIN", "PRODUCT": "PRODUCTO",
+		"PROPER": "NOMPROPIO", "PV": "VA", "QUARTILE": "CUARTIL", "QUARTILE.EXC": "CUARTIL.EXC",
+		"QUARTILE.INC": "CUARTIL.INC", "QUOTIENT": "COCIENTE", "RADIANS": "RADIANES", "RAND": "ALEATORIO",
+		"RANDBETWEEN": "ALEATORIO.ENTRE", "RANK": "JERARQUIA", "RANK.AVG": "JERARQUIA.MEDIA",
+		"RANK.EQ": "JERARQUIA.EQV", "RATE": "TASA", "REPLACE": "REEMPLAZAR", "REPT": "REPETIR", "RIGHT": "DERECHA",
+		"ROMAN": "NUMERO.ROMANO", "ROUND": "REDONDEAR", "ROUNDDOWN": "REDONDEAR.MENOS", "ROUNDUP": "REDONDEAR.MAS",
+		"ROW": "FILA", "ROWS": "FILAS", "RRI": "RRI", "RSQ": "COEFICIENTE.R2", "SEARCH": "HALLAR", "SEC": "SEC",
+		"SECH": "SECH", "SECOND": "SEGUNDO", "SEQUENCE": "SECUENCIA", "SERIESSUM": "SUMA.SERIES", "SIGN": "SIGNO",
+		"SIN": "SENO", "SINH": "SENOH", "SKEW": "COEFICIENTE.ASIMETRIA", "SLN": "SLN", "SLOPE": "PENDIENTE",
+		"SMALL": "K.ESIMO.MENOR", "SORT": "ORDENAR", "SQRT": "RAIZ", "SQRTPI": "RAIZ2PI",
+		"STANDARDIZE": "NORMALIZACION", "STDEV": "DESVEST", "STDEV.P": "DESVEST.P", "STDEV.S": "DESVEST.M",
+		"STDEVA": "DESVESTA", "STDEVP": "DESVESTP", "STDEVPA": "DESVESTPA", "STEYX": "ERROR.TIPICO.XY",
+		"SUBSTITUTE": "SUSTITUIR", "SUBTOTAL": "SUBTOTALES", "SUM": "SUMA", "SUMIF": "SUMAR.SI",
+		"SUMIFS": "SUMAR.SI.CONJUNTO", "SUMPRODUCT": "SUMAPRODUCTO", "SUMSQ": "SUMA.CUADRADOS",
+		"SUMX2MY2": "SUMAX2MENOSY2", "SUMX2PY2": "SUMAX2MASY2", "SUMXMY2": "SUMAXMENOSY2", "SWITCH": "CAMBIAR",
+		"SYD": "SYD", "T": "T", "TAN": "TAN", "TANH": "TANH", "TEXT": "TEXTO", "TEXTAFTER": "TEXTODESPUES",
+		"TEXTBEFORE": "TEXTOANTES", "TEXTJOIN": "UNIRCADENAS", "TIME": "NSHORA", "TIMEVALUE": "HORANUMERO",
+		"TODAY": "HOY", "TRANSPOSE": "TRANSPONER", "TRIM": "ESPACIOS", "TRIMMEAN": "MEDIA.ACOTADA",
+		"TRUE": "VERDADERO", "TRUNC": "TRUNCAR", "TYPE": "TIPO", "UNICHAR": "UNICAR", "UNICODE": "UNICODE",
+		"UNIQUE": "UNICOS", "UPPER": "MAYUSC", "VALUE": "VALOR", "VAR": "VAR", "VAR.P": "VAR.P", "VAR.S": "VAR.S",
+		"VARA": "VARA", "VARP": "VARP", "VARPA": "VARPA", "VLOOKUP": "BUSCARV", "WEEKDAY": "DIASEM",
+		"WEEKNUM": "NUM.DE.SEMANA", "WORKDAY": "DIA.LAB", "XLOOKUP": "BUSCARX", "XMATCH": "COINCIDIRX",
+		"XOR": "XO", "YEAR": "AÑO", "YEARFRAC": "FRAC.AÑO",
+	},
+	Portuguese: {
+		"ABS": "ABS", "ACOS": "ACOS", "ACOSH": "ACOSH", "ACOT": "ACOT", "ACOTH": "ACOTH", "ADDRESS": "ENDEREÇO",
+		"AND": "E", "ARABIC": "ARÁBICO", "AREAS": "ÁREAS", "ASIN": "ASEN", "ASINH": "ASENH", "ATAN": "ATAN",
+		"ATAN2": "ATAN2", "ATANH": "ATANH", "AVEDEV": "DESV.MÉDIO", "AVERAGE": "MÉDIA", "AVERAGEA": "MÉDIAA",
+		"AVERAGEIF": "MÉDIASE", "AVERAGEIFS": "MÉDIASES", "BASE": "BASE", "BIN2DEC": "BINADEC",
+		"BIN2HEX": "BINAHEX", "BIN2OCT": "BINAOCT", "BINOM.DIST": "DISTR.BINOM", "BINOMDIST": "DISTRBINOM",
+		"BITAND": "BITAND", "BITLSHIFT": "DESLOCESQBIT", "BITOR": "BITOR", "BITRSHIFT": "DESLOCDIRBIT",
+		"BITXOR": "BITXOR", "CEILING": "TETO", "CEILING.MATH": "TETO.MAT", "CEILING.PRECISE": "TETO.PRECISO",
+		"CHAR": "CARACT", "CHOOSE": "ESCOLHER", "CLEAN": "TIRAR", "CODE": "CÓDIGO", "COLUMN": "COL",
+		"COLUMNS": "COLS", "COMBIN": "COMBIN", "COMBINA": "COMBINA", "CONCAT": "CONCAT",
+		"CONCATENATE": "CONCATENAR", "CONFIDENCE": "INT.CONFIANÇA", "CONFIDENCE.NORM": "INT.CONFIANÇA.NORM",
+		"CORREL": "CORREL", "COS": "COS", "COSH": "COSH", "COT": "COT", "COTH": "COTH", "COUNT": "CONT.NÚM",
+		"COUNTA": "CONT.VALORES", "COUNTBLANK": "CONTAR.VAZIO", "COUNTIF": "CONT.SE", "COUNTIFS": "CONT.SES",
+		"COVAR": "COVAR", "COVARIANCE.P": "COVARIAÇÃO.P", "COVARIANCE.S": "COVARIAÇÃO.S", "CSC": "COSEC",
+		"CSCH": "COSECH", "DATE": "DATA", "DATEDIF": "DATADIF", "DATEVALUE": "DATA.VALOR", "DAY": "DIA",
+		"DAYS": "DIAS", "DAYS360": "DIAS360", "DDB": "BDD", "DEC2BIN": "DECABIN", "DEC2HEX": "DECAHEX",
+		"DEC2OCT": "DECAOCT", "DECIMAL": "DECIMAL", "DEGREES": "GRAUS", "DELTA": "DELTA", "DEVSQ": "DESVQ",
+		"DOLLAR": "MOEDA", "EDATE": "DATAM", "EFFECT": "EFETIVA", "EOMONTH": "FIMMÊS", "ERROR.TYPE": "TIPO.ERRO",
+		"EVEN": "PAR", "EXACT": "EXATO", "EXP": "EXP", "EXPON.DIST": "DISTR.EXPON", "EXPONDIST": "DISTEXPON",
+		"FACT": "FATORIAL", "FACTDOUBLE": "FATDUPLO", "FALSE": "FALSO", "FILTER": "FILTRO", "FIND": "PROCURAR",
+		"FISHER": "FISHER", "FISHERINV": "FISHERINV", "FIXED": "DEF.NÚM.DEC", "FLOOR": "ARREDMULTB",
+		"FLOOR.MATH": "ARREDMULTB.MAT", "FLOOR.PRECISE": "ARREDMULTB.PRECISO", "FORECAST": "PREVISÃO",
+		"FORECAST.LINEAR": "PREVISÃO.LINEAR", "FV": "VF", "FVSCHEDULE": "VFPLANO", "GAMMA": "GAMA",
+		"GAMMALN": "LNGAMA", "GAMMALN.PRECISE": "LNGAMA.PRECISO", "GAUSS": "GAUSS", "GCD": "MDC",
+		"GEOMEAN": "MÉDIA.GEOMÉTRICA", "GESTEP": "DEGRAU", "HARMEAN": "MÉDIA.HARMÔNICA", "HEX2BIN": "HEXABIN",
+		"HEX2DEC": "HEXADEC", "HEX2OCT": "HEXAOCT", "HLOOKUP": "PROCH", "HOUR": "HORA", "IF": "SE",
+		"IFERROR": "SEERRO", "IFNA": "SENÃODISP", "IFS": "SES", "INDEX": "ÍNDICE", "INDIRECT": "INDIRETO",
+		"INT": "INT", "INTERCEPT": "INTERCEPÇÃO", "IPMT": "IPGTO", "IRR": "TIR", "ISBLANK": "ÉCÉL.VAZIA",
+		"ISERR": "ÉERRO", "ISERROR": "ÉERROS", "ISEVEN": "ÉPAR", "ISLOGICAL": "ÉLÓGICO", "ISNA": "É.NÃO.DISP",
+		"ISNONTEXT": "É.NÃO.TEXTO", "ISNUMBER": "ÉNÚM", "ISO.CEILING": "ISO.TETO", "ISODD": "ÉIMPAR",
+		"ISOWEEKNUM": "NÚMSEMANAISO", "ISREF": "ÉREF", "ISTEXT": "ÉTEXTO", "KURT": "CURT", "LARGE": "MAIOR",
+		"LCM": "MMC", "LEFT": "ESQUERDA", "LEN": "NÚM.CARACT", "LN": "LN", "LOG": "LOG", "LOG10": "LOG10",
+		"LOOKUP": "PROC", "LOWER": "MINÚSCULA", "MATCH": "CORRESP", "MAX": "MÁXIMO", "MAXA": "MÁXIMOA",
+		"MAXIFS": "MÁXIMOSES", "MEDIAN": "MED", "MID": "EXT.TEXTO", "MIN": "MÍNIMO", "MINA": "MÍNIMOA",
+		"MINIFS": "MÍNIMOSES", "MINUTE": "MINUTO", "MOD": "MOD", "MODE": "MODO", "MODE.SNGL": "MODO.ÚNICO",
+		"MONTH": "MÊS", "MROUND": "MARRED", "MULTINOMIAL": "MULTINOMIAL", "N": "N", "NA": "NÃO.DISP",
+		"NETWORKDAYS": "DIATRABALHOTOTAL", "NOMINAL": "NOMINAL", "NORM.DIST": "DIST.NORM.N",
+		"NORM.INV": "INV.NORM.N", "NORM.S.DIST": "DIST.NORMP.N", "NORM.S.INV": "INV.NORMP.N",
+		"NORMDIST": "DIST.NORM", "NORMINV": "INV.NORM", "NORMSDIST": "DIST.NORMP", "NORMSINV": "INV.NORMP",
+		"NOT": "NÃO", "NOW": "AGORA", "NPER": "NPER", "NPV": "VPL", "NUMBERVALUE": "VALORNUMÉRICO",
+		"OCT2BIN": "OCTABIN", "OCT2DEC": "OCTADEC", "OCT2HEX": "OCTAHEX", "ODD": "ÍMPAR", "OFFSET": "DESLOC",
+		"OR": "OU", "PDURATION": "DURAÇÃOP", "PEARSON": "PEARSON", "PERCENTILE": "PERCENTIL",
+		"PERCENTILE.EXC": "PERCENTIL.EXC", "PERCENTILE.INC": "PERCENTIL.INC", "PERCENTRANK": "ORDEM.PORCENTUAL",
+		"PERCENTRANK.INC": "ORDEM.PORCENTUAL.INC", "PERMUT": "PERMUT", "PERMUTATIONA": "PERMUTAS", "PHI": "PHI",
+		"PI": "PI", "PMT": "PGTO", "POISSON": "POISSON", "POISSON.DIST": "DIST.POISSON", "POWER": "POTÊNCIA",
+		"PPMT": "PPGTO", "PRODUCT": "MULT", "PROPER": "PRI.MAIÚSCULA", "PV": "VP", "QUARTILE": "QUARTIL",
+		"QUARTILE.EXC": "QUARTIL.EXC", "QUARTILE.INC": "QUARTIL.INC", "QUOTIENT": "QUOCIENTE",
+		"RADIANS": "RADIANOS", "RAND": "ALEATÓRIO", "RANDBETWEEN": "ALEATÓRIOENTRE", "RANK": "ORDEM",
+		"RANK.AVG": "ORDEM.MÉD", "RANK.EQ": "ORDEM.EQ", "RATE": "TAXA", "REPLACE": "MUDAR", "REPT": "REPT",
+		"RIGHT": "DIREITA", "ROMAN": "ROMANO", "ROUND": "ARRED", "ROUNDDOWN": "ARREDONDAR.PARA.BAIXO",
+		"ROUNDUP": "ARREDONDAR.PARA.CIMA", "ROW": "LIN", "ROWS": "LINS", "RRI": "TAXAJUROS", "RSQ": "RQUAD",
+		"SEARCH": "LOCALIZAR", "SEC": "SEC", "SECH": "SECH", "SECOND": "SEGUNDO", "SEQUENCE": "SEQUÊNCIA",
+		"SERIESSUM": "SOMASEQÜÊNCIA", "SIGN": "SINAL", "SIN": "SEN", "SINH": "SENH", "SKEW": "DISTORÇÃO",
+		"SLN": "DPD", "SLOPE": "INCLINAÇÃO", "SMALL": "MENOR", "SORT": "CLASSIFICAR", "SQRT": "RAIZ",
+		"SQRTPI": "RAIZPI", "STANDARDIZE": "PADRONIZAR", "STDEV": "DESVPAD", "STDEV.P": "DESVPAD.P",
+		"STDEV.S": "DESVPAD.A", "STDEVA": "DESVPADA", "STDEVP": "DESVPADP", "STDEVPA": "DESVPADPA",
+		"STEYX": "EPADYX", "SUBSTITUTE": "SUBSTITUIR", "SUBTOTAL": "SUBTOTAL", "SUM": "SOMA", "SUMIF": "SOMASE",
+		"SUMIFS": "SOMASES", "SUMPRODUCT": "SOMARPRODUTO", "SUMSQ": "SOMAQUAD", "SUMX2MY2": "SOMAX2DY2",
+		"SUMX2PY2": "SOMAX2SY2", "SUMXMY2": "SOMAXMY2", "SWITCH": "PARÂMETRO", "SYD": "SDA", "T": "T",
+		"TAN": "TAN", "TANH": "TANH", "TEXT": "TEXTO", "TEXTAFTER": "TEXTODEPOIS", "TEXTBEFORE": "TEXTOANTES",
+		"TEXTJOIN": "UNIRTEXTO", "TIME": "TEMPO", "TIMEVALUE": "VALOR.TEMPO", "TODAY": "HOJE",
+		"TRANSPOSE": "TRANSPOR", "TRIM": "ARRUMAR", "TRIMMEAN": "MÉDIA.INTERNA", "TRUE": "VERDADEIRO",
+		"TRUNC": "TRUNCAR", "TYPE": "TIPO", "UNICHAR": "CARACTUNICODE", "UNICODE": "UNICODE", "UNIQUE": "ÚNICO",
+		"UPPER": "MAIÚSCULA", "VALUE": "VALOR", "VAR": "VAR", "VAR.P": "VAR.P", "VAR.S": "VAR.A", "VARA": "VARA",
+		"VARP": "VARP", "VARPA": "VARPA", "VLOOKUP": "PROCV", "WEEKDAY": "DIA.DA.SEMANA", "WEEKNUM": "NÚMSEMANA",
+		"WORKDAY": "DIATRABALHO", "XLOOKUP": "PROCX", "XMATCH": "CORRESPX", "XOR": "OUEXCL", "YEAR": "ANO",
+		"YEARFRAC": "FRAÇÃOANO",
+	},
+	Italian: {
+		"ABS": "ASS", "ACOS": "ARCCOS", "ACOSH": "ARCCOSH", "ACOT": "ARCCOT", "ACOTH": "ARCCOTH",
+		"ADDRESS": "INDIRIZZO", "AND": "E", "ARABIC": "ARABO", "AREAS": "AREE", "ASIN": "ARCSEN",
+		"ASINH": "ARCSENH", "ATAN": "ARCTAN", "ATAN2": "ARCTAN.2", "ATANH": "ARCTANH", "AVEDEV": "MEDIA.DEV",
+		"AVERAGE": "MEDIA", "AVERAGEA": "MEDIA.VALORI", "AVERAGEIF": "MEDIA.SE", "AVERAGEIFS": "MEDIA.PIÙ.SE",
+		"BASE": "BASE", "BIN2DEC": "BINARIO.DECIMALE", "BIN2HEX": "BINARIO.HEX", "BIN2OCT": "BINARIO.OCT",
+		"BINOM.DIST": "DISTRIB.BINOM.N", "BINOMDIST": "DISTRIB.BINOM", "BITAND": "BITAND",
+		"BITLSHIFT": "BIT.SPOSTA.SX", "BITOR": "BITOR", "BITRSHIFT": "BIT.SPOSTA.DX", "BITXOR": "BITXOR",
+		"CEILING": "ARROTONDA.ECCESSO", "CEILING.MATH": "ARROTONDA.ECCESSO.MAT",
+		"CEILING.PRECISE": "ARROTONDA.ECCESSO.PRECISA", "CHAR": "CODICE.CARATT", "CHOOSE": "SCEGLI",
+		"CLEAN": "LIBERA", "CODE": "CODICE", "COLUMN": "RIF.COLONNA", "COLUMNS": "COLONNE",
+		"COMBIN": "COMBINAZIONE", "COMBINA": "COMBINAZIONE.VALORI", "CONCAT": "CONCAT", "CONCATENATE": "CONCATENA",
+		"CONFIDENCE": "CONFIDENZA", "CONFIDENCE.NORM": "CONFIDENZA.NORM", "CORREL": "CORRELAZIONE", "COS": "COS",
+		"COSH": "COSH", "COT": "COT", "COTH": "COTH", "COUNT": "CONTA.NUMERI", "COUNTA": "CONTA.VALORI",
+		"COUNTBLANK": "CONTA.VUOTE", "COUNTIF": "CONTA.SE", "COUNTIFS": "CONTA.PIÙ.SE", "COVAR": "COVARIANZA",
+		"COVARIANCE.P": "COVARIANZA.P", "COVARIANCE.S": "COVARIANZA.C", "CSC": "CSC", "CSCH": "CSCH",
+		"DATE": "DATA", "DATEDIF": "DATA.DIFF", "DATEVALUE": "DATA.VALORE", "DAY": "GIORNO", "DAYS": "GIORNI",
+		"DAYS360": "GIORNO360", "DDB": "AMMORT", "DEC2BIN": "DECIMALE.BINARIO", "DEC2HEX": "DECIMALE.HEX",
+		"DEC2OCT": "DECIMALE.OCT", "DECIMAL": "DECIMALE", "DEGREES": "GRADI", "DELTA": "DELTA", "DEVSQ": "DEV.Q",
+		"DOLLAR": "VALUTA", "EDATE": "DATA.MESE", "EFFECT": "EFFETTIVO", "EOMONTH": "FINE.MESE",
+		"ERROR.TYPE": "ERRORE.TIPO", "EVEN": "PARI", "EXACT": "IDENTICO", "EXP": "EXP",
+		"EXPON.DIST": "DISTRIB.EXP.N", "EXPONDIST": "DISTRIB.EXP", "FACT": "FATTORIALE",
+		"FACTDOUBLE": "FATT.DOPPIO", "FALSE": "FALSO", "FILTER": "FILTRO", "FIND": "TROVA", "FISHER": "FISHER",
+		"FISHERINV": "INV.FISHER", "FIXED": "FISSO", "FLOOR": "ARROTONDA.DIFETTO",
+		"FLOOR.MATH": "ARROTONDA.DIFETTO.MAT", "FLOOR.PRECISE": "ARROTONDA.DIFETTO.PRECISA",
+		"FORECAST": "PREVISIONE", "FORECAST.LINEAR": "PREVISIONE.LINEARE", "FV": "VAL.FUT",
+		"FVSCHEDULE": "VAL.FUT.CAPITALE", "GAMMA": "GAMMA", "GAMMALN": "LN.GAMMA",
+		"GAMMALN.PRECISE": "LN.GAMMA.PRECISA", "GAUSS": "GAUSS", "GCD": "MCD", "GEOMEAN": "MEDIA.GEOMETRICA",
+		"GESTEP": "SOGLIA", "HARMEAN": "MEDIA.ARMONICA", "HEX2BIN": "HEX.BINARIO", "HEX2DEC": "HEX.DECIMALE",
+		"HEX2OCT": "HEX.OCT", "HLOOKUP": "CERCA.ORIZZ", "HOUR": "ORA", "IF": "SE", "IFERROR": "SE.ERRORE",
+		"IFNA": "SE.NON.DISP.", "IFS": "PIÙ.SE", "INDEX": "INDICE", "INDIRECT": "INDIRETTO", "INT": "INT",
+		"INTERCEPT": "INTERCETTA", "IPMT": "INTERESSI", "IRR": "TIR.COST", "ISBLANK": "VAL.VUOTO",
+		"ISERR": "VAL.ERR", "ISERROR": "VAL.ERRORE", "ISEVEN": "VAL.PARI", "ISLOGICAL": "VAL.LOGICO",
+		"ISNA": "VAL.NON.DISP", "ISNONTEXT": "VAL.NON.TESTO", "ISNUMBER": "VAL.NUMERO",
+		"ISO.CEILING": "ISO.ARROTONDA.ECCESSO", "ISODD": "VAL.DISPARI", "ISOWEEKNUM": "NUM.SETTIMANA.ISO",
+		"ISREF": "VAL.RIF", "ISTEXT": "VAL.TESTO", "KURT": "CURTOSI", "LARGE": "GRANDE", "LCM": "MCM",
+		"LEFT": "SINISTRA", "LEN": "LUNGHEZZA", "LN": "LN", "LOG": "LOG", "LOG10": "LOG10", "LOOKUP": "CERCA",
+		"LOWER": "MINUSC", "MATCH": "CONFRONTA", "MAX": "MAX", "MAXA": "MAX.VALORI", "MAXIFS": "MAX.PIÙ.SE",
+		"MEDIAN": "MEDIANA", "MID": "STRINGA.ESTRAI", "MIN": "MIN", "MINA": "MIN.VALORI", "MINIFS": "MIN.PIÙ.SE",
+		"MINUTE": "MINUTO", "MOD": "RESTO", "MODE": "MODA", "MODE.SNGL": "MODA.SNGL", "MONTH": "MESE",
+		"MROUND": "ARROTONDA.MULTIPLO", "MULTINOMIAL": "MULTINOMIALE", "N": "NUM", "NA": "NON.DISP",
+		"NETWORKDAYS": "GIORNI.LAVORATIVI.TOT", "NOMINAL": "NOMINALE", "NORM.DIST": "DISTRIB.NORM.N",
+		"NORM.INV": "INV.NORM.N", "NORM.S.DIST": "DISTRIB.NORM.ST.N", "NORM.S.INV": "INV.NORM.S",
+		"NORMDIST": "DISTRIB.NORM", "NORMINV": "INV.NORM", "NORMSDIST": "DISTRIB.NORM.ST",
+		"NORMSINV": "INV.NORM.ST", "NOT": "NON", "NOW": "ADESSO", "NPER": "NUM.RATE", "NPV": "VAN",
+		"NUMBERVALUE": "NUMERO.VALORE", "OCT2BIN": "OCT.BINARIO", "OCT2DEC": "OCT.DECIMALE", "OCT2HEX": "OCT.HEX",
+		"ODD": "DISPARI", "OFFSET": "SCARTO", "OR": "O", "PDURATION": "DURATA.P", "PEARSON": "PEARSON",
+		"PERCENTILE": "PERCENTILE", "PERCENTILE.EXC": "ESC.PERCENTILE", "PERCENTILE.INC": "INC.PERCENTILE",
+		"PERCENTRANK": "PERCENT.RANGO", "PERCENTRANK.INC": "INC.PERCENT.RANGO", "PERMUT": "PERMUTAZIONE",
+		"PERMUTATIONA": "PERMUTAZIONE.VALORI", "PHI": "PHI", "PI": "PI.GRECO", "PMT": "RATA", "POISSON": "POISSON",
+		"POISSON.DIST": "DISTRIB.POISSON", "POWER": "POTENZA", "PPMT": "P.RATA", "PRODUCT": "PRODOTTO",
+		"PROPER": "MAIUSC.INIZ", "PV": "VA", "QUARTILE": "QUARTILE", "QUARTILE.EXC": "ESC.QUARTILE",
+		"QUARTILE.INC": "INC.QUARTILE", "QUOTIENT": "QUOZIENTE", "RADIANS": "RADIANTI", "RAND": "CASUALE",
+		"RANDBETWEEN": "CASUALE.TRA", "RANK": "RANGO", "RANK.AVG": "RANGO.MEDIA", "RANK.EQ": "RANGO.UG",
+		"RATE": "TASSO", "REPLACE": "RIMPIAZZA", "REPT": "RIPETI", "RIGHT": "DESTRA", "ROMAN": "ROMANO",
+		"ROUND": "ARROTONDA", "ROUNDDOWN": "ARROTONDA.PER.DIF", "ROUNDUP": "ARROTONDA.PER.ECC", "ROW": "RIF.RIGA",
+		"ROWS": "RIGHE", "RRI": "RIT.INVEST.EFFETT", "RSQ": "RQ", "SEARCH": "RICERCA", "SEC": "SEC",
+		"SECH": "SECH", "SECOND": "SECONDO", "SEQUENCE": "SEQUENZA", "SERIESSUM": "SOMMA.SERIE", "SIGN": "SEGNO",
+		"SIN": "SEN", "SINH": "SENH", "SKEW": "ASIMMETRIA", "SLN": "AMMORT.COST", "SLOPE": "PENDENZA",
+		"SMALL": "PICCOLO", "SORT": "DATI.ORDINA", "SQRT": "RADQ", "SQRTPI": "RADQ.PI.GRECO",
+		"STANDARDIZE": "NORMALIZZA", "STDEV": "DEV.ST", "STDEV.P": "DEV.ST.P", "STDEV.S": "DEV.ST.C",
+		"STDEVA": "DEV.ST.VALORI", "STDEVP": "DEV.ST.POP", "STDEVPA": "DEV.ST.POP.VALORI", "STEYX": "ERR.STD.YX",
+		"SUBSTITUTE": "SOSTITUISCI", "SUBTOTAL": "SUBTOTALE", "SUM": "SOMMA", "SUMIF": "SOMMA.SE",
+		"SUMIFS": "SOMMA.PIÙ.SE", "SUMPRODUCT": "MATR.SOMMA.PRODOTTO", "SUMSQ": "SOMMA.Q",
+		"SUMX2MY2": "SOMMA.DIFF.Q", "SUMX2PY2": "SOMMA.SOMMA.Q", "SUMXMY2": "SOMMA.Q.DIFF", "SWITCH": "SWITCH",
+		"SYD": "AMMORT.ANNUO", "T": "T", "TAN": "TAN", "TANH": "TANH", "TEXT": "TESTO", "TEXTAFTER": "TESTO.DOPO",
+		"TEXTBEFORE": "TESTO.PRIMA", "TEXTJOIN": "TESTO.UNISCI", "TIME": "ORARIO", "TIMEVALUE": "ORARIO.VALORE",
+		"TODAY": "OGGI", "TRANSPOSE": "MATR.TRASPOSTA", "TRIM": "ANNULLA.SPAZI", "TRIMMEAN": "MEDIA.TRONCATA",
+		"TRUE": "VERO", "TRUNC": "TRONCA", "TYPE": "TIPO", "UNICHAR": "CARATT.UNI", "UNICODE": "UNICODE",
+		"UNIQUE": "UNICI", "UPPER": "MAIUSC", "VALUE": "VALORE", "VAR": "VAR", "VAR.P": "VAR.P", "VAR.S": "VAR.C",
+		"VARA": "VAR.VALORI", "VARP": "VAR.POP", "VARPA": "VAR.POP.VALORI", "VLOOKUP": "CERCA.VERT",
+		"WEEKDAY": "GIORNO.SETTIMANA", "WEEKNUM": "NUM.SETTIMANA", "WORKDAY": "GIORNO.LAVORATIVO",
+		"XLOOKUP": "CERCA.X", "XMATCH": "CONFRONTA.X", "XOR": "XOR", "YEAR": "ANNO", "YEARFRAC": "FRAZIONE.ANNO",
+	},
+	Dutch: {
+		"ABS": "ABS", "ACOS": "BOOGCOS", "ACOSH": "BOOGCOSH", "ACOT": "BOOGCOT", "ACOTH": "BOOGCOTH",
+		"ADDRESS": "ADRES", "AND": "EN", "ARABIC": "ARABISCH", "AREAS": "BEREIKEN", "ASIN": "BOOGSIN",
+		"ASINH": "BOOGSINH", "ATAN": "BOOGTAN", "ATAN2": "BOOGTAN2", "ATANH": "BOOGTANH", "AVEDEV": "GEM.DEVIATIE",
+		"AVERAGE": "GEMIDDELDE", "AVERAGEA": "GEMIDDELDEA", "AVERAGEIF": "GEMIDDELDE.ALS",
+		"AVERAGEIFS": "GEMIDDELDEN.ALS", "BASE": "BASIS", "BIN2DEC": "BIN.N.DEC", "BIN2HEX": "BIN.N.HEX",
+		"BIN2OCT": "BIN.N.OCT", "BINOM.DIST": "BINOM.VERD", "BINOMDIST": "BINOMIALE.VERD", "BITAND": "BIT.EN",
+		"BITLSHIFT": "BIT.VERSCHUIF.LINKS", "BITOR": "BIT.OF", "BITRSHIFT": "BIT.VERSCHUIF.RECHTS",
+		"BITXOR": "BIT.EX.OF", "CEILING": "AFRONDEN.BOVEN", "CEILING.MATH": "AFRONDEN.BOVEN.WISK",
+		"CEILING.PRECISE": "AFRONDEN.BOVEN.NAUWKEURIG", "CHAR": "TEKEN", "CHOOSE": "KIEZEN",
+		"CLEAN": "WISSEN.CONTROL", "CODE": "CODE", "COLUMN": "KOLOM", "COLUMNS": "KOLOMMEN",
+		"COMBIN": "COMBINATIES", "COMBINA": "COMBIN.A", "CONCAT": "TEKST.SAMENV",
+		"CONCATENATE": "TEKST.SAMENVOEGEN", "CONFIDENCE": "BETROUWBAARHEID",
+		"CONFIDENCE.NORM": "VERTROUWELIJKHEID.NORM", "CORREL": "CORRELATIE", "COS": "COS", "COSH": "COSH",
+		"COT": "COT", "COTH": "COTH", "COUNT": "AANTAL", "COUNTA": "AANTALARG", "COUNTBLANK": "AANTAL.LEGE.CELLEN",
+		"COUNTIF": "AANTAL.ALS", "COUNTIFS": "AANTALLEN.ALS", "COVAR": "COVARIANTIE",
+		"COVARIANCE.P": "COVARIANTIE.P", "COVARIANCE.S": "COVARIANTIE.S", "CSC": "COSEC", "CSCH": "COSECH",
+		"DATE": "DATUM", "DATEDIF": "DATUMVERSCHIL", "DATEVALUE": "DATUMWAARDE", "DAY": "DAG", "DAYS": "DAGEN",
+		"DAYS360": "DAGEN360", "DDB": "DDB", "DEC2BIN": "DEC.N.BIN", "DEC2HEX": "DEC.N.HEX",
+		"DEC2OCT": "DEC.N.OCT", "DECIMAL": "DECIMAAL", "DEGREES": "GRADEN", "DELTA": "DELTA", "DEVSQ": "DEV.KWAD",
+		"DOLLAR": "EURO", "EDATE": "ZELFDE.DAG", "EFFECT": "EFFECT.RENTE", "EOMONTH": "LAATSTE.DAG",
+		"ERROR.TYPE": "TYPE.FOUT", "EVEN": "EVEN", "EXACT": "GELIJK", "EXP": "EXP", "EXPON.DIST": "EXPON.VERD.N",
+		"EXPONDIST": "EXPON.VERD", "FACT": "FACULTEIT", "FACTDOUBLE": "DUBBELE.FACULTEIT", "FALSE": "ONWAAR",
+		"FILTER": "FILTER", "FIND": "VIND.ALLES", "FISHER": "FISHER", "FISHERINV": "FISHER.INV", "FIXED": "VAST",
+		"FLOOR": "AFRONDEN.BENEDEN", "FLOOR.MATH": "AFRONDEN.BENEDEN.WISK",
+		"FLOOR.PRECISE": "AFRONDEN.BENEDEN.NAUWKEURIG", "FORECAST": "VOORSPELLEN",
+		"FORECAST.LINEAR": "VOORSPELLEN.LINEAR", "FV": "TW", "FVSCHEDULE": "TOEK.WAARDE2", "GAMMA": "GAMMA",
+		"GAMMALN": "GAMMA.LN", "GAMMALN.PRECISE": "GAMMA.LN.NAUWKEURIG", "GAUSS": "GAUSS", "GCD": "GGD",
+		"GEOMEAN": "MEETK.GEM", "GESTEP": "GROTER.DAN", "HARMEAN": "HARM.GEM", "HEX2BIN": "HEX.N.BIN",
+		"HEX2DEC": "HEX.N.DEC", "HEX2OCT": "HEX.N.OCT", "HLOOKUP": "HORIZ.ZOEKEN", "HOUR": "UUR", "IF": "ALS",
+		"IFERROR": "ALS.FOUT", "IFNA": "ALS.NB", "IFS": "ALS.VOORWAARDEN", "INDEX": "INDEX",
+		"INDIRECT": "INDIRECT", "INT": "INTEGER", "INTERCEPT": "SNIJPUNT", "IPMT": "IBET", "IRR": "IR",
+		"ISBLANK": "ISLEEG", "ISERR": "ISFOUT2", "ISERROR": "ISFOUT", "ISEVEN": "IS.EVEN",
+		"ISLOGICAL": "ISLOGISCH", "ISNA": "ISNB", "ISNONTEXT": "ISGEENTEKST", "ISNUMBER": "ISGETAL",
+		"ISO.CEILING": "ISO.PLAFOND", "ISODD": "IS.ONEVEN", "ISOWEEKNUM": "ISO.WEEKNUMMER",
+		"ISREF": "ISVERWIJZING", "ISTEXT": "ISTEKST", "KURT": "KURTOSIS", "LARGE": "GROOTSTE", "LCM": "KGV",
+		"LEFT": "LINKS", "LEN": "LENGTE", "LN": "LN", "LOG": "LOG", "LOG10": "LOG10", "LOOKUP": "ZOEKEN",
+		"LOWER": "KLEINE.LETTERS", "MATCH": "VERGELIJKEN", "MAX": "MAX", "MAXA": "MAXA",
+		"MAXIFS": "MAX.ALS.VOORWAARDEN", "MEDIAN": "MEDIAAN", "MID": "DEEL", "MIN": "MIN", "MINA": "MINA",
+		"MINIFS": "MIN.ALS.VOORWAARDEN", "MINUTE": "MINUUT", "MOD": "REST", "MODE": "MODUS",
+		"MODE.SNGL": "MODUS.ENKELV", "MONTH": "MAAND", "MROUND": "AFRONDEN.N.VEELVOUD",
+		"MULTINOMIAL": "MULTINOMIAAL", "N": "N", "NA": "NB", "NETWORKDAYS": "NETTO.WERKDAGEN",
+		"NOMINAL": "NOMINALE.RENTE", "NORM.DIST": "NORM.VERD.N", "NORM.INV": "NORM.INV.N",
+		"NORM.S.DIST": "NORM.S.VERD", "NORM.S.INV": "NORM.S.INV", "NORMDIST": "NORM.VERD", "NORMINV": "NORM.INV",
+		"NORMSDIST": "STAND.NORM.VERD", "NORMSINV": "STAND.NORM.INV", "NOT": "NIET", "NOW": "NU", "NPER": "NPER",
+		"NPV": "NHW", "NUMBERVALUE": "NUMERIEKE.WAARDE", "OCT2BIN": "OCT.N.BIN", "OCT2DEC": "OCT.N.DEC",
+		"OCT2HEX": "OCT.N.HEX", "ODD": "ONEVEN", "OFFSET": "VERSCHUIVING", "OR": "OF", "PDURATION": "PDUUR",
+		"PEARSON": "PEARSON", "PERCENTILE": "PERCENTIEL", "PERCENTILE.EXC": "PERCENTIEL.EXC",
+		"PERCENTILE.INC": "PERCENTIEL.INC", "PERCENTRANK": "PERCENT.RANG", "PERCENTRANK.INC": "PROCENTRANG.INC",
+		"PERMUT": "PERMUTATIES", "PERMUTATIONA": "PERMUTATIE.A", "PHI": "PHI", "PI": "PI", "PMT": "BET",
+		"POISSON": "POISSON", "POISSON.DIST": "POISSON.VERD", "POWER": "MACHT", "PPMT": "PBET",
+		"PRODUCT": "PRODUCT", "PROPER": "BEGINLETTERS", "PV": "HW", "QUARTILE": "KWARTIEL",
+		"QUARTILE.EXC": "KWARTIEL.EXC", "QUARTILE.INC": "KWARTIEL.INC", "QUOTIENT": "QUOTIENT",
+		"RADIANS": "RADIALEN", "RAND": "ASELECT", "RANDBETWEEN": "ASELECTTUSSEN", "RANK": "RANG",
+		"RANK.AVG": "RANG.GEMIDDELDE", "RANK.EQ": "RANG.GELIJK", "RATE": "RENTE", "REPLACE": "VERVANGEN",
+		"REPT": "HERHALING", "RIGHT": "RECHTS", "ROMAN": "ROMEINS", "ROUND": "AFRONDEN",
+		"ROUNDDOWN": "AFRONDEN.NAAR.BENEDEN", "ROUNDUP": "AFRONDEN.NAAR.BOVEN", "ROW": "RIJ", "ROWS": "RIJEN",
+		"RRI": "RRI", "RSQ": "R.KWADRAAT", "SEARCH": "VIND.SPEC", "SEC": "SEC", "SECH": "SECH",
+		"SECOND": "SECONDE", "SEQUENCE": "REEKS", "SERIESSUM": "SOM.MACHTREEKS", "SIGN": "POS.NEG", "SIN": "SIN",
+		"SINH": "SINH", "SKEW": "SCHEEFHEID", "SLN": "LIN.AFSCHR", "SLOPE": "RICHTING", "SMALL": "KLEINSTE",
+		"SORT": "SORTEREN", "SQRT": "WORTEL", "SQRTPI": "WORTEL.PI", "STANDARDIZE": "NORMALISEREN",
+		"STDEV": "STDEV", "STDEV.P": "STDEV.P", "STDEV.S": "STDEV.S", "STDEVA": "STDEVA", "STDEVP": "STDEVP",
+		"STDEVPA": "STDEVPA", "STEYX": "STAND.FOUT.YX", "SUBSTITUTE": "SUBSTITUEREN", "SUBTOTAL": "SUBTOTAAL",
+		"SUM": "SOM", "SUMIF": "SOM.ALS", "SUMIFS": "SOMMEN.ALS", "SUMPRODUCT": "SOMPRODUCT",
+		"SUMSQ": "KWADRATENSOM", "SUMX2MY2": "SOM.X2MINY2", "SUMX2PY2": "SOM.X2PLUSY2", "SUMXMY2": "SOM.XMINY.2",
+		"SWITCH": "SCHAKELEN", "SYD": "SYD", "T": "T", "TAN": "TAN", "TANH": "TANH", "TEXT": "TEKST",
+		"TEXTAFTER": "TEKST.NA", "TEXTBEFORE": "TEKST.VOOR", "TEXTJOIN": "TEKST.COMBINEREN", "TIME": "TIJD",
+		"TIMEVALUE": "TIJDWAARDE", "TODAY": "VANDAAG", "TRANSPOSE": "TRANSPONEREN", "TRIM": "SPATIES.WISSEN",
+		"TRIMMEAN": "GETRIMD.GEM", "TRUE": "WAAR", "TRUNC": "GEHEEL", "TYPE": "TYPE", "UNICHAR": "UNITEKEN",
+		"UNICODE": "UNICODE", "UNIQUE": "UNIEK", "UPPER": "HOOFDLETTERS", "VALUE": "WAARDE", "VAR": "VAR",
+		"VAR.P": "VAR.P", "VAR.S": "VAR.S", "VARA": "VARA", "VARP": "VARP", "VARPA": "VARPA",
+		"VLOOKUP": "VERT.ZOEKEN", "WEEKDAY": "WEEKDAG", "WEEKNUM": "WEEKNUMMER", "WORKDAY": "WERKDAG",
+		"XLOOKUP": "X.ZOEKEN", "XMATCH": "X.VERGELIJKEN", "XOR": "EX.OF", "YEAR": "JAAR", "YEARFRAC": "JAAR.DEEL",
+	},
+}
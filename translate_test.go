@@ -0,0 +1,138 @@
+package xlsxformula
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTranslateFunctionName(t *testing.T) {
+	RegisterFunction("MYFUNC", 0, 0, func(args []Value) Value {
+		return NewNumber(1)
+	})
+	tests := []struct {
+		name     string
+		from     Language
+		to       Language
+		expected string
+	}{
+		{"summe", German, English, "SUM"},
+		{"SUM", English, French, "SOMME"},
+		{"ZÄHLENWENN", German, Spanish, "CONTAR.SI"},
+		{"ÉCÉL.VAZIA", Portuguese, Dutch, "ISLEEG"},
+		{"ABS", German, Italian, "ASS"},
+		{"MIN", German, Portuguese, "MÍNIMO"},
+		{"_xlfn.XVERWEIS", German, English, "_xlfn.XLOOKUP"},
+		{"SUM", English, Japanese, "SUM"},
+		{"MyFunc", German, English, "MYFUNC"},
+		{"ZEIT", German, English, "TIME"},
+		{"KÜRZEN", German, English, "TRUNC"},
+		{"kgrösste", German, English, "LARGE"},
+		{"ISTNV", German, French, "ESTNA"},
+		{"ARBEITSTAG", German, Dutch, "WERKDAG"},
+		{"VAR.P", French, English, "VARP"},
+	}
+	for _, test := range tests {
+		actual, err := TranslateFunctionName(test.name, test.from, test.to)
+		if err != nil {
+			t.Errorf("err of %s should be nil, but %v", test.name, err)
+		} else if actual != test.expected {
+			t.Errorf("%s from %s to %s should be %s, but %s", test.name, test.from, test.to, test.expected, actual)
+		}
+	}
+	if _, err := TranslateFunctionName("SUM", English, Language("xx")); err == nil {
+		t.Errorf("unsupported language should be error, but nil")
+	}
+	for _, name := range []string{"ZEITX", "SUM", "_xlfn.FOO"} {
+		var unknown *UnknownFunctionError
+		if _, err := TranslateFunctionName(name, German, English); !errors.As(err, &unknown) {
+			t.Errorf("%s in German should be *UnknownFunctionError, but %v", name, err)
+		}
+	}
+}
+
+func TestFunctionNames(t *testing.T) {
+	for language, names := range functionNames {
+		if len(englishNames[language]) != len(names) {
+			t.Errorf("localized names of %s should be unique", language)
+		}
+		for english := range names {
+			if !IsFunction(english) {
+				t.Errorf("%s of %s should be a builtin function", english, language)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		functionsLock.RLock()
+		for english, spec := range functions {
+			if _, ok := names[english]; !ok && !spec.user {
+				t.Errorf("%s should have the name in %s", english, language)
+			}
+		}
+		functionsLock.RUnlock()
+	}
+}
+
+func TestTranslateFunctions(t *testing.T) {
+	formula := `WENN(SUMME(A1:A3)>0;MITTELWERT(B1:B3);ZÄHLENWENN(C1:C3;">0"))`
+	node, err := ParseWithOptions(formula, ParseOptions{Separators: germanSeparators})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	english, err := TranslateFunctions(node, German, English)
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if formatted := Format(english, FormatOptions{}); formatted != `IF(SUM(A1:A3)>0,AVERAGE(B1:B3),COUNTIF(C1:C3,">0"))` {
+		t.Errorf("English formula should be IF(SUM(A1:A3)>0,AVERAGE(B1:B3),COUNTIF(C1:C3,\">0\")), but %s", formatted)
+	}
+	if Format(node, FormatOptions{Separators: germanSeparators}) != formula {
+		t.Errorf("original node should not be changed, but %s", Format(node, FormatOptions{}))
+	}
+	french, _ := TranslateFunctions(english, English, French)
+	if formatted := Format(french, FormatOptions{Separators: germanSeparators}); formatted != `SI(SOMME(A1:A3)>0;MOYENNE(B1:B3);NB.SI(C1:C3;">0"))` {
+		t.Errorf("French formula should be SI(SOMME(A1:A3)>0;MOYENNE(B1:B3);NB.SI(C1:C3;\">0\")), but %s", formatted)
+	}
+	if _, err := TranslateFunctions(node, German, Language("xx")); err == nil {
+		t.Errorf("unsupported language should be error, but nil")
+	}
+	mixed, _ := ParseWithOptions("SUMME(A1)+SUM(A1)+ZEITX(1)", ParseOptions{Separators: germanSeparators})
+	translated, err := TranslateFunctions(mixed, German, English)
+	var unknown *UnknownFunctionError
+	if !errors.As(err, &unknown) || len(unknown.Names) != 2 || unknown.Names[0] != "SUM" || unknown.Names[1] != "ZEITX" {
+		t.Errorf("unknown names should be SUM and ZEITX, but %v", err)
+	}
+	if formatted := Format(translated, FormatOptions{}); formatted != "SUM(A1)+SUM(A1)+ZEITX(1)" {
+		t.Errorf("unknown names should be kept, but %s", formatted)
+	}
+}
+
+func TestTranslateBooleans(t *testing.T) {
+	formula := "WENN(1>2;WAHR;FALSCH)"
+	node, err := ParseWithOptions(formula, ParseOptions{Separators: germanSeparators})
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	english, err := TranslateFunctions(node, German, English)
+	if err != nil {
+		t.Fatalf("err should be nil, but %v", err)
+	}
+	if formatted := Format(english, FormatOptions{}); formatted != "IF(1>2,TRUE,FALSE)" {
+		t.Errorf("English formula should be IF(1>2,TRUE,FALSE), but %s", formatted)
+	}
+	if value, err := Evaluate(english, nil); err != nil || value.String() != "FALSE" {
+		t.Errorf("result should be FALSE, but %s (%v)", value.String(), err)
+	}
+	french, _ := TranslateFunctions(english, English, French)
+	if formatted := Format(french, FormatOptions{Separators: germanSeparators}); formatted != "SI(1>2;VRAI;FAUX)" {
+		t.Errorf("French formula should be SI(1>2;VRAI;FAUX), but %s", formatted)
+	}
+	german, _ := TranslateFunctions(french, French, German)
+	if formatted := Format(german, FormatOptions{Separators: germanSeparators}); formatted != formula {
+		t.Errorf("German formula should be %s, but %s", formula, formatted)
+	}
+	name, _ := ParseWithOptions("WAHR+1", ParseOptions{})
+	if translated, _ := TranslateFunctions(name, English, German); translated.Children[0].Token.Type != Name {
+		t.Errorf("WAHR in English should be a defined name, but %v", translated.Children[0].Token.Type)
+	}
+}
@@ -0,0 +1,315 @@
+package xlsxformula
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type ValueType int
+
+const (
+	BlankValue  ValueType = iota // empty cell or missing argument
+	NumberValue                  // number (dates are serial numbers)
+	StringValue                  // text
+	BoolValue                    // TRUE/FALSE
+	ErrorValue                   // #DIV/0! etc
+	ArrayValue                   // range or array: rows of values
+)
+
+func (vt ValueType) String() string {
+	switch vt {
+	case BlankValue:
+		return "Blank"
+	case NumberValue:
+		return "Number"
+	case StringValue:
+		return "String"
+	case BoolValue:
+		return "Bool"
+	case ErrorValue:
+		return "Error"
+	case ArrayValue:
+		return "Array"
+	}
+	return "Unknown"
+}
+
+// ErrorCode is Excel's error literal like "#DIV/0!".
+type ErrorCode string
+
+const (
+	NullError        ErrorCode = "#NULL!"
+	Div0Error        ErrorCode = "#DIV/0!"
+	ValueError       ErrorCode = "#VALUE!"
+	RefError         ErrorCode = "#REF!"
+	NameError        ErrorCode = "#NAME?"
+	NumError         ErrorCode = "#NUM!"
+	NAError          ErrorCode = "#N/A"
+	SpillError       ErrorCode = "#SPILL!"
+	CalcError        ErrorCode = "#CALC!"
+	GettingDataError ErrorCode = "#GETTING_DATA"
+)
+
+var errorCodes map[string]ErrorCode = map[string]ErrorCode{
+	"#NULL!":        NullError,
+	"#DIV/0!":       Div0Error,
+	"#VALUE!":       ValueError,
+	"#REF!":         RefError,
+	"#NAME?":        NameError,
+	"#NUM!":         NumError,
+	"#N/A":          NAError,
+	"#SPILL!":       SpillError,
+	"#CALC!":        CalcError,
+	"#GETTING_DATA": GettingDataError,
+}
+
+// Value is a result of evaluation or a cell value returned by EvalContext.
+// Only the field that matches Type is meaningful.
+type Value struct {
+	Type   ValueType
+	Number float64
+	Text   string
+	Bool   bool
+	Error  ErrorCode
+	Array  [][]Value
+}
+
+func NewNumber(number float64) Value {
+	return Value{Type: NumberValue, Number: number}
+}
+
+func NewString(text string) Value {
+	return Value{Type: StringValue, Text: text}
+}
+
+func NewBool(b bool) Value {
+	return Value{Type: BoolValue, Bool: b}
+}
+
+func NewError(code ErrorCode) Value {
+	return Value{Type: ErrorValue, Error: code}
+}
+
+// NewArray creates an ArrayValue. All rows should have the same length.
+func NewArray(rows [][]Value) Value {
+	return Value{Type: ArrayValue, Array: rows}
+}
+
+func (v Value) IsError() bool {
+	return v.Type == ErrorValue
+}
+
+// String returns the text that Excel shows in a cell for the value.
+// Arrays are written as array constants like {1,"a";TRUE,#N/A}.
+func (v Value) String() string {
+	switch v.Type {
+	case NumberValue:
+		return formatNumber(v.Number)
+	case StringValue:
+		return v.Text
+	case BoolValue:
+		if v.Bool {
+			return "TRUE"
+		}
+		return "FALSE"
+	case ErrorValue:
+		return string(v.Error)
+	case ArrayValue:
+		var buffer bytes.Buffer
+		buffer.WriteByte('{')
+		for i, row := range v.Array {
+			if i != 0 {
+				buffer.WriteByte(';')
+			}
+			for j, cell := range row {
+				if j != 0 {
+					buffer.WriteByte(',')
+				}
+				if cell.Type == StringValue {
+					buffer.WriteByte('"')
+					buffer.WriteString(strings.Replace(cell.Text, `"`, `""`, -1))
+					buffer.WriteByte('"')
+				} else {
+					buffer.WriteString(cell.String())
+				}
+			}
+		}
+		buffer.WriteByte('}')
+		return buffer.String()
+	}
+	return ""
+}
+
+// formatNumber formats number like Excel's General format: up to 15 significant digits.
+func formatNumber(number float64) string {
+	if number == 0 {
+		return "0"
+	}
+	text := strconv.FormatFloat(number, 'g', 15, 64)
+	if index := strings.IndexByte(text, 'e'); index != -1 {
+		return strings.ToUpper(text[:index]) + "E" + text[index+1:]
+	}
+	return text
+}
+
+// numberResult converts the result of arithmetic to a Value. NaN and infinity become #NUM!.
+func numberResult(number float64) Value {
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return NewError(NumError)
+	}
+	return NewNumber(number)
+}
+
+// parseNumber parses text like " 12.5 ", "1e3" or "50%" as Excel does when
+// text is used as a number.
+func parseNumber(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, false
+	}
+	percent := false
+	if strings.HasSuffix(text, "%") {
+		percent = true
+		text = strings.TrimSpace(text[:len(text)-1])
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	if percent {
+		number /= 100
+	}
+	return number, true
+}
+
+// scalar returns the top-left value of an array, or the value itself.
+func scalar(v Value) Value {
+	if v.Type == ArrayValue {
+		if len(v.Array) == 0 || len(v.Array[0]) == 0 {
+			return NewError(ValueError)
+		}
+		return v.Array[0][0]
+	}
+	return v
+}
+
+// toNumber coerces v to a number. It returns non-empty ErrorCode if v can't be a number.
+func toNumber(v Value) (float64, ErrorCode) {
+	v = scalar(v)
+	switch v.Type {
+	case BlankValue:
+		return 0, ""
+	case NumberValue:
+		return v.Number, ""
+	case BoolValue:
+		if v.Bool {
+			return 1, ""
+		}
+		return 0, ""
+	case StringValue:
+		if number, ok := parseNumber(v.Text); ok {
+			return number, ""
+		}
+		return 0, ValueError
+	case ErrorValue:
+		return 0, v.Error
+	}
+	return 0, ValueError
+}
+
+// toText coerces v to a string.
+func toText(v Value) (string, ErrorCode) {
+	v = scalar(v)
+	if v.Type == ErrorValue {
+		return "", v.Error
+	}
+	return v.String(), ""
+}
+
+// toBool coerces v to a boolean. Text is accepted only when it is "TRUE" or "FALSE".
+func toBool(v Value) (bool, ErrorCode) {
+	v = scalar(v)
+	switch v.Type {
+	case BlankValue:
+		return false, ""
+	case NumberValue:
+		return v.Number != 0, ""
+	case BoolValue:
+		return v.Bool, ""
+	case StringValue:
+		switch strings.ToUpper(strings.TrimSpace(v.Text)) {
+		case "TRUE":
+			return true, ""
+		case "FALSE":
+			return false, ""
+		}
+		return false, ValueError
+	case ErrorValue:
+		return false, v.Error
+	}
+	return false, ValueError
+}
+
+// compareValues compares values with Excel's ordering: numbers < text < FALSE < TRUE.
+// Text is compared case-insensitively. Blank is treated as the zero value of the other side.
+func compareValues(a, b Value) int {
+	if a.Type == BlankValue && b.Type == BlankValue {
+		return 0
+	}
+	if a.Type == BlankValue {
+		a = zeroOf(b.Type)
+	} else if b.Type == BlankValue {
+		b = zeroOf(a.Type)
+	}
+	rankA := typeRank(a.Type)
+	rankB := typeRank(b.Type)
+	if rankA != rankB {
+		if rankA < rankB {
+			return -1
+		}
+		return 1
+	}
+	switch a.Type {
+	case NumberValue:
+		if a.Number < b.Number {
+			return -1
+		} else if a.Number > b.Number {
+			return 1
+		}
+		return 0
+	case StringValue:
+		return strings.Compare(strings.ToLower(a.Text), strings.ToLower(b.Text))
+	case BoolValue:
+		if a.Bool == b.Bool {
+			return 0
+		} else if b.Bool {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func zeroOf(vt ValueType) Value {
+	switch vt {
+	case StringValue:
+		return NewString("")
+	case BoolValue:
+		return NewBool(false)
+	}
+	return NewNumber(0)
+}
+
+func typeRank(vt ValueType) int {
+	switch vt {
+	case NumberValue:
+		return 0
+	case StringValue:
+		return 1
+	case BoolValue:
+		return 2
+	}
+	return 3
+}